@@ -0,0 +1,134 @@
+package harmonica
+
+// This file defines an aim solver for projectiles: given where a projectile
+// starts, where it should land and the acceleration acting on it, it computes
+// the launch velocity needed to get there.
+//
+// Example usage:
+//
+//    // Throw something at (40, 0, 0) from the origin at 30 units/second.
+//    arcs := Aim(Point{0, 0, 0}, Point{40, 0, 0}, Gravity, 30)
+//    if len(arcs) > 0 {
+//        projectile := NewProjectile(FPS(60), Point{0, 0, 0}, arcs[0], Gravity)
+//    }
+//
+// For background on the math see:
+// https://en.wikipedia.org/wiki/Projectile_motion#Angle_%CE%B8_required_to_hit_coordinate_(x,_y)
+
+import "math"
+
+// Aim computes the launch velocities that will carry a projectile from one
+// point to another at the given speed under a constant acceleration, such as
+// Gravity or TerminalGravity.
+//
+// There are zero, one or two solutions. When the target is out of range for
+// the given speed no velocities are returned. When there are two solutions the
+// low (faster) arc is returned first and the high (slower) arc second.
+//
+// The solution is exact for continuous motion. Projectile integrates motion
+// in discrete steps, so a projectile launched with one of these velocities
+// will land very close to, but not precisely on, the target. The smaller the
+// time delta, the closer it will land.
+func Aim(from, to Point, gravity Vector, speed float64) []Vector {
+	var (
+		d  = to.Sub(from)
+		dd = d.LengthSquared()
+		dg = d.Dot(gravity)
+		gg = gravity.LengthSquared()
+		ss = speed * speed
+	)
+
+	if speed <= 0 {
+		return nil
+	}
+
+	// Already there.
+	if dd < epsilon {
+		return nil
+	}
+
+	// Without acceleration there's only a straight line.
+	if gg < epsilon {
+		return []Vector{aimVelocity(d, gravity, math.Sqrt(dd/ss))}
+	}
+
+	// Solving |d - ½gt²| = st for the flight time t gives a quadratic in t²:
+	//
+	//     ¼|g|²t⁴ - (d·g + s²)t² + |d|² = 0
+	//
+	var (
+		a    = 0.25 * gg
+		b    = -(dg + ss)
+		disc = b*b - 4*a*dd
+	)
+
+	if disc < 0 {
+		return nil
+	}
+
+	sqrtDisc := math.Sqrt(disc)
+	if sqrtDisc < epsilon {
+		t := math.Sqrt(-b / (2 * a))
+		return []Vector{aimVelocity(d, gravity, t)}
+	}
+
+	var vels []Vector
+	for _, tt := range [2]float64{(-b - sqrtDisc) / (2 * a), (-b + sqrtDisc) / (2 * a)} {
+		if tt <= 0 {
+			continue
+		}
+		vels = append(vels, aimVelocity(d, gravity, math.Sqrt(tt)))
+	}
+	return vels
+}
+
+// AimMinSpeed computes the launch velocity that carries a projectile from one
+// point to another with the lowest possible launch speed under a constant
+// acceleration. It returns the velocity along with its speed.
+//
+// If there's no acceleration any speed will do, so a zero velocity and speed
+// are returned.
+func AimMinSpeed(from, to Point, gravity Vector) (vel Vector, speed float64) {
+	var (
+		d  = to.Sub(from)
+		dd = d.LengthSquared()
+		dg = d.Dot(gravity)
+		gg = gravity.LengthSquared()
+	)
+
+	if dd < epsilon || gg < epsilon {
+		return Vector{}, 0
+	}
+
+	// The minimum speed is where the discriminant in Aim reaches zero:
+	//
+	//     s² = |g||d| - d·g
+	//
+	// ...which yields a flight time of t² = 2|d|/|g|.
+	var (
+		dl = math.Sqrt(dd)
+		gl = math.Sqrt(gg)
+		t  = math.Sqrt(2 * dl / gl)
+	)
+
+	speed = math.Sqrt(math.Max(0, gl*dl-dg))
+	return aimVelocity(d, gravity, t), speed
+}
+
+// AimTime computes the launch velocity that carries a projectile from one
+// point to another in exactly the given flight time, in seconds, under
+// a constant acceleration.
+//
+// A non-positive flight time has no solution and returns a zero velocity.
+func AimTime(from, to Point, gravity Vector, flightTime float64) Vector {
+	if flightTime <= 0 {
+		return Vector{}
+	}
+	return aimVelocity(to.Sub(from), gravity, flightTime)
+}
+
+// aimVelocity returns the launch velocity that covers the displacement d in
+// time t under acceleration g, solving d = vt + ½gt² for v.
+func aimVelocity(d, g Vector, t float64) Vector {
+	return d.Scale(1 / t).Sub(g.Scale(0.5 * t))
+}
@@ -0,0 +1,132 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+// landing returns where a projectile launched from p with velocity v under
+// acceleration a will be after t seconds of continuous motion.
+func landing(p Point, v, a Vector, t float64) Point {
+	return Point{
+		X: p.X + v.X*t + 0.5*a.X*t*t,
+		Y: p.Y + v.Y*t + 0.5*a.Y*t*t,
+		Z: p.Z + v.Z*t + 0.5*a.Z*t*t,
+	}
+}
+
+// hits reports whether a projectile launched from p with velocity v under
+// acceleration a passes through the target at some point in time.
+func hits(p, target Point, v, a Vector) bool {
+	const steps = 100000
+	for i := 1; i <= steps; i++ {
+		pos := landing(p, v, a, float64(i)/steps*20)
+		if equal(pos.X, target.X) && equal(pos.Y, target.Y) && equal(pos.Z, target.Z) {
+			return true
+		}
+	}
+	return false
+}
+
+func speedOf(v Vector) float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+func TestAim(t *testing.T) {
+	from := Point{0, 0, 0}
+	to := Point{40, 5, 3}
+
+	vels := Aim(from, to, Gravity, 30)
+	if len(vels) != 2 {
+		t.Fatalf("expected two arcs, got %d", len(vels))
+	}
+
+	if vels[0].Y >= vels[1].Y {
+		t.Logf("Low:  %+v", vels[0])
+		t.Logf("High: %+v", vels[1])
+		t.Fatal("expected the low arc first")
+	}
+
+	for _, v := range vels {
+		if !equal(speedOf(v), 30) {
+			t.Logf("Want: %.2f", 30.0)
+			t.Logf("Got:  %.2f", speedOf(v))
+			t.Fatal("launch speed unexpected")
+		}
+		if !hits(from, to, v, Gravity) {
+			t.Fatalf("velocity %+v misses the target", v)
+		}
+	}
+}
+
+func TestAimOutOfRange(t *testing.T) {
+	if vels := Aim(Point{0, 0, 0}, Point{1000, 0, 0}, Gravity, 10); len(vels) != 0 {
+		t.Fatalf("expected no solutions, got %d", len(vels))
+	}
+}
+
+func TestAimNoGravity(t *testing.T) {
+	vels := Aim(Point{0, 0, 0}, Point{3, 4, 0}, Vector{}, 10)
+	if len(vels) != 1 {
+		t.Fatalf("expected one solution, got %d", len(vels))
+	}
+	if !equal(vels[0].X, 6) || !equal(vels[0].Y, 8) {
+		t.Logf("Want: (%.2f, %.2f)", 6.0, 8.0)
+		t.Logf("Got:  (%.2f, %.2f)", vels[0].X, vels[0].Y)
+		t.Fatal("velocity unexpected")
+	}
+}
+
+func TestAimMinSpeed(t *testing.T) {
+	from := Point{0, 0, 0}
+	to := Point{40, 0, 0}
+
+	v, speed := AimMinSpeed(from, to, Gravity)
+
+	// On flat ground the optimal angle is 45° and the speed is √(gd).
+	if want := math.Sqrt(9.81 * 40); !equal(speed, want) {
+		t.Logf("Want: %.2f", want)
+		t.Logf("Got:  %.2f", speed)
+		t.Fatal("minimum speed unexpected")
+	}
+	if !equal(speedOf(v), speed) {
+		t.Fatal("velocity doesn't match reported speed")
+	}
+	if !hits(from, to, v, Gravity) {
+		t.Fatalf("velocity %+v misses the target", v)
+	}
+
+	// Any slower and there's no solution; any faster and there are two.
+	if n := len(Aim(from, to, Gravity, speed-0.1)); n != 0 {
+		t.Fatalf("expected no solutions below minimum speed, got %d", n)
+	}
+	if n := len(Aim(from, to, Gravity, speed+0.1)); n != 2 {
+		t.Fatalf("expected two solutions above minimum speed, got %d", n)
+	}
+}
+
+func TestAimTime(t *testing.T) {
+	from := Point{2, 10, 0}
+	to := Point{20, 0, 0}
+
+	v := AimTime(from, to, TerminalGravity, 1.5)
+	pos := landing(from, v, TerminalGravity, 1.5)
+	if !equal(pos.X, to.X) || !equal(pos.Y, to.Y) || !equal(pos.Z, to.Z) {
+		t.Logf("Want: (%.2f, %.2f, %.2f)", to.X, to.Y, to.Z)
+		t.Logf("Got:  (%.2f, %.2f, %.2f)", pos.X, pos.Y, pos.Z)
+		t.Fatal("landing position unexpected")
+	}
+
+	// Stepping a Projectile should land close by, too.
+	p := NewProjectile(FPS(fps), from, v, TerminalGravity)
+	for i := 0; i < int(1.5*fps); i++ {
+		pos = p.Update()
+	}
+	if math.Abs(pos.X-to.X) > 0.5 || math.Abs(pos.Y-to.Y) > 0.5 {
+		t.Logf("Want: (%.2f, %.2f)", to.X, to.Y)
+		t.Logf("Got:  (%.2f, %.2f)", pos.X, pos.Y)
+		t.Fatal("stepped projectile missed the target")
+	}
+}
@@ -0,0 +1,289 @@
+// Package animation drives harmonica springs and projectiles from a Bubble
+// Tea program. A Model owns a set of springs and projectiles, ticks while any
+// of them are moving, and stops ticking once everything has settled, so idle
+// programs don't redraw 60 times a second.
+//
+// Example usage:
+//
+//    type model struct {
+//        anim *animation.Model
+//        x    *animation.Spring
+//    }
+//
+//    func newModel() model {
+//        anim := animation.New(60)
+//        return model{anim: anim, x: anim.AddSpring(6.0, 0.3, 0)}
+//    }
+//
+//    func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+//        switch msg := msg.(type) {
+//        case tea.KeyMsg:
+//            return m, m.x.SetTarget(60)
+//        case animation.FrameMsg:
+//            return m, m.anim.Update(msg)
+//        }
+//        return m, nil
+//    }
+//
+//    func (m model) View() string {
+//        return strings.Repeat(" ", int(m.x.Position())) + "*"
+//    }
+package animation
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/harmonica"
+)
+
+// DefaultThreshold is how close to its target, and how slow, a spring has to
+// be to count as settled. It's the same as harmonica.DefaultThreshold.
+//
+// The settling logic here mirrors harmonica.Group, but is kept private so this
+// package only needs the springs and projectiles of a released harmonica.
+const DefaultThreshold = 0.01
+
+// lastID is used to give each Model a unique ID, so frames are only handled
+// by the model that requested them.
+var lastID int64
+
+// FrameMsg is sent on every animation frame. Pass it to the Update method of
+// the Model it's for.
+type FrameMsg struct {
+	Time time.Time
+	id   int64
+	tag  int
+}
+
+// Model runs a set of springs and projectiles, ticking while any of them are
+// moving. Create one with New.
+//
+// Unlike the components in the Bubbles library, a Model is used through
+// a pointer and its Update method changes it in place, returning only
+// a command. That's because the springs it hands out refer back to it, so that
+// changing one can start the model ticking again; a copy of the model returned
+// from Update would leave them pointing at a stale one.
+type Model struct {
+	// Threshold is how close to its target, and how slow, a spring has to be
+	// to count as settled. It defaults to DefaultThreshold.
+	Threshold float64
+
+	id          int64
+	tag         int
+	fps         int
+	deltaTime   float64
+	running     bool
+	springs     []*Spring
+	projectiles []*Projectile
+}
+
+// New creates a new model that ticks at the given number of frames per
+// second.
+func New(fps int) *Model {
+	if fps < 1 {
+		fps = 1
+	}
+	return &Model{
+		Threshold: DefaultThreshold,
+		id:        atomic.AddInt64(&lastID, 1),
+		fps:       fps,
+		deltaTime: harmonica.FPS(fps),
+	}
+}
+
+// Spring is a value animated by a spring, owned by a Model.
+type Spring struct {
+	model    *Model
+	spring   harmonica.Spring
+	pos, vel float64
+	target   float64
+}
+
+// AddSpring adds a value animated by a spring with the given angular frequency
+// and damping ratio, as for harmonica.NewSpring, starting at rest at the given
+// position.
+func (m *Model) AddSpring(angularFrequency, dampingRatio, pos float64) *Spring {
+	s := &Spring{
+		model:  m,
+		spring: harmonica.NewSpring(m.deltaTime, angularFrequency, dampingRatio),
+		pos:    pos,
+		target: pos,
+	}
+	m.springs = append(m.springs, s)
+	return s
+}
+
+// RemoveSpring stops animating a spring.
+func (m *Model) RemoveSpring(s *Spring) {
+	for i, v := range m.springs {
+		if v == s {
+			m.springs = append(m.springs[:i], m.springs[i+1:]...)
+			return
+		}
+	}
+}
+
+// Position returns the current value of the spring.
+func (s *Spring) Position() float64 {
+	return s.pos
+}
+
+// Velocity returns the current velocity of the spring.
+func (s *Spring) Velocity() float64 {
+	return s.vel
+}
+
+// Target returns the value the spring is moving towards.
+func (s *Spring) Target() float64 {
+	return s.target
+}
+
+// SetTarget sets the value the spring moves towards. It starts the model
+// ticking if it isn't already, and returns the command to do so, which should
+// be returned from your Update method.
+func (s *Spring) SetTarget(target float64) tea.Cmd {
+	s.target = target
+	return s.model.Start()
+}
+
+// SetPosition jumps the spring to a value, keeping its velocity. Like
+// SetTarget, it returns a command to start ticking if needed.
+func (s *Spring) SetPosition(pos float64) tea.Cmd {
+	s.pos = pos
+	return s.model.Start()
+}
+
+// SetVelocity sets the velocity of the spring, such as to give it a nudge.
+// Like SetTarget, it returns a command to start ticking if needed.
+func (s *Spring) SetVelocity(vel float64) tea.Cmd {
+	s.vel = vel
+	return s.model.Start()
+}
+
+// settled returns whether the spring is at rest at its target.
+func (s *Spring) settled(threshold float64) bool {
+	return math.Abs(s.pos-s.target) < threshold && math.Abs(s.vel) < threshold
+}
+
+// Projectile is a projectile owned by a Model.
+type Projectile struct {
+	*harmonica.Projectile
+	done     func(*harmonica.Projectile) bool
+	finished bool
+}
+
+// AddProjectile adds a projectile to animate. It keeps moving until done
+// reports true, such as when it hits the ground or leaves the screen, after
+// which it's no longer updated. If done is nil, it keeps moving until it's
+// removed. It returns the command to start ticking if the model isn't
+// already, as SetTarget does.
+//
+// The projectile should be initialized with the same time delta as the
+// model, harmonica.FPS(fps).
+func (m *Model) AddProjectile(p *harmonica.Projectile, done func(*harmonica.Projectile) bool) (*Projectile, tea.Cmd) {
+	proj := &Projectile{Projectile: p, done: done}
+	m.projectiles = append(m.projectiles, proj)
+	return proj, m.Start()
+}
+
+// RemoveProjectile stops animating a projectile.
+func (m *Model) RemoveProjectile(p *Projectile) {
+	for i, v := range m.projectiles {
+		if v == p {
+			m.projectiles = append(m.projectiles[:i], m.projectiles[i+1:]...)
+			return
+		}
+	}
+}
+
+// settled returns whether the projectile has finished moving, remembering the
+// answer so done isn't called again.
+func (p *Projectile) settled() bool {
+	if !p.finished && p.done != nil && p.done(p.Projectile) {
+		p.finished = true
+	}
+	return p.finished
+}
+
+// Start starts ticking if anything needs animating and the model isn't
+// already ticking, and returns the command to do so. Changing a spring or
+// adding a projectile calls it for you, so it's mostly needed after Stop. It's
+// safe to call at any time, but the model counts as ticking from the moment
+// the command is returned, so the command must be run.
+func (m *Model) Start() tea.Cmd {
+	if m.running || m.Settled() {
+		return nil
+	}
+	m.running = true
+	m.tag++
+	return m.tick()
+}
+
+// Stop stops ticking. Anything still moving freezes until Start is called
+// again.
+func (m *Model) Stop() {
+	m.running = false
+}
+
+// Running returns whether the model is ticking.
+func (m *Model) Running() bool {
+	return m.running
+}
+
+// Settled returns whether every spring is at rest at its target and every
+// projectile is done.
+func (m *Model) Settled() bool {
+	for _, s := range m.springs {
+		if !s.settled(m.Threshold) {
+			return false
+		}
+	}
+	for _, p := range m.projectiles {
+		if !p.settled() {
+			return false
+		}
+	}
+	return true
+}
+
+// Update advances everything by one frame when given a FrameMsg for this
+// model, and returns the command for the next frame. Once everything has
+// settled it snaps springs to their targets and stops ticking. Other messages
+// are ignored.
+func (m *Model) Update(msg tea.Msg) tea.Cmd {
+	frame, ok := msg.(FrameMsg)
+	if !ok || frame.id != m.id || frame.tag != m.tag || !m.running {
+		return nil
+	}
+
+	for _, s := range m.springs {
+		if !s.settled(m.Threshold) {
+			s.pos, s.vel = s.spring.Update(s.pos, s.vel, s.target)
+		}
+	}
+	for _, p := range m.projectiles {
+		if !p.settled() {
+			p.Update()
+		}
+	}
+
+	if m.Settled() {
+		for _, s := range m.springs {
+			s.pos, s.vel = s.target, 0
+		}
+		m.running = false
+		return nil
+	}
+	return m.tick()
+}
+
+// tick returns a command that sends the next frame.
+func (m *Model) tick() tea.Cmd {
+	id, tag := m.id, m.tag
+	return tea.Tick(time.Second/time.Duration(m.fps), func(t time.Time) tea.Msg {
+		return FrameMsg{Time: t, id: id, tag: tag}
+	})
+}
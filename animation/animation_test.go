@@ -0,0 +1,78 @@
+package animation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/harmonica"
+)
+
+// run calls Update with frames until the model stops asking for them, and
+// returns the number of frames.
+func run(t *testing.T, m *Model, cmd func() FrameMsg) int {
+	var frames int
+	for m.Running() {
+		if frames > 60*60 {
+			t.Fatal("animation never settled")
+		}
+		m.Update(cmd())
+		frames++
+	}
+	return frames
+}
+
+func TestSpringSettles(t *testing.T) {
+	m := New(60)
+	s := m.AddSpring(8, 0.5, 0)
+
+	if cmd := m.Start(); cmd != nil {
+		t.Fatal("expected no ticking while at rest")
+	}
+
+	if cmd := s.SetTarget(40); cmd == nil {
+		t.Fatal("expected ticking to start after retargeting")
+	}
+	if cmd := m.Start(); cmd != nil {
+		t.Fatal("expected a second Start not to tick twice")
+	}
+
+	frames := run(t, m, func() FrameMsg { return FrameMsg{Time: time.Now(), id: m.id, tag: m.tag} })
+	if s.Position() != 40 || s.Velocity() != 0 || frames < 10 {
+		t.Fatalf("expected spring to settle at 40, got %.2f after %d frames", s.Position(), frames)
+	}
+}
+
+func TestStaleFrames(t *testing.T) {
+	m := New(60)
+	s := m.AddSpring(8, 0.5, 0)
+	other := New(60)
+
+	s.SetTarget(10)
+	stale := FrameMsg{id: m.id, tag: m.tag}
+	m.Stop()
+	m.Start()
+
+	// Frames from an earlier run or for another model are ignored, so
+	// restarting doesn't double the frame rate.
+	for _, msg := range []FrameMsg{stale, {id: other.id, tag: m.tag}} {
+		if cmd := m.Update(msg); cmd != nil || s.Position() != 0 {
+			t.Fatalf("expected frame %+v to be ignored", msg)
+		}
+	}
+}
+
+func TestProjectileDone(t *testing.T) {
+	m := New(60)
+	p, cmd := m.AddProjectile(
+		harmonica.NewProjectile(harmonica.FPS(60), harmonica.Point{}, harmonica.Vector{X: 10}, harmonica.Vector{}),
+		func(p *harmonica.Projectile) bool { return p.Position().X >= 5 },
+	)
+	if cmd == nil {
+		t.Fatal("expected ticking to start after adding a projectile")
+	}
+	run(t, m, func() FrameMsg { return FrameMsg{id: m.id, tag: m.tag} })
+
+	if x := p.Position().X; x < 5 || x > 5.2 {
+		t.Fatalf("expected projectile to stop at 5, got %.2f", x)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/charmbracelet/harmonica/animation
+
+go 1.16
+
+replace github.com/charmbracelet/harmonica => ../
+
+require (
+	github.com/charmbracelet/bubbletea v0.14.1
+	github.com/charmbracelet/harmonica v0.0.0-20210709144143-16876a63b30d
+	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
+)
@@ -0,0 +1,360 @@
+package harmonica
+
+// This file defines an animator, which runs springs and projectiles on its own
+// goroutine in real time, for programs without an update loop of their own,
+// such as command line tools and servers streaming progress. Frames are
+// published on a channel, to a callback, or both, and targets can be changed
+// from any goroutine.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    anim := NewAnimator(60)
+//    bar := anim.AddSpring(6.0, 1.0, 0)
+//    anim.Start(ctx)
+//
+//    // From anywhere, as progress is made.
+//    anim.SetTarget(bar, 0.75)
+//
+//    // Draw frames as they arrive, until ctx is cancelled.
+//    for frame := range anim.Frames() {
+//        draw(frame.Positions[bar])
+//    }
+//
+// The animator stops ticking once everything has settled, and starts again
+// when a target changes, until the context passed to Start is cancelled. Then
+// the frames channel is closed, which ends the loop above.
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrAnimatorRunning is returned by Animator.Run when the animator is already
+// running.
+var ErrAnimatorRunning = errors.New("harmonica: animator already running")
+
+// Frame is the state of everything an Animator runs, after one time step.
+type Frame struct {
+	Time time.Time
+
+	// Positions and Velocities hold the state of each spring, indexed as
+	// returned by AddSpring.
+	Positions  []float64
+	Velocities []float64
+
+	// Points holds the position of each projectile, indexed as returned by
+	// AddProjectile.
+	Points []Point
+
+	// Settled is true on the last frame before the animator stops ticking.
+	Settled bool
+}
+
+// Animator runs a Group of springs and projectiles on a goroutine. Its methods
+// are safe to call from multiple goroutines.
+type Animator struct {
+	mu       sync.Mutex
+	interval time.Duration
+	group    *Group
+	onFrame  func(Frame)
+	running  bool
+	ctx      context.Context
+
+	// Springs and projectiles in the order they were added, which is how
+	// they're indexed.
+	springs     []*GroupSpring
+	projectiles []*GroupProjectile
+
+	// Frames are published outside the main lock, so callbacks can call back
+	// into the animator. Each gets a sequence number so that a goroutine
+	// finishing up can't publish a frame older than one already published.
+	publishMu sync.Mutex
+	seq       uint64
+	published uint64
+
+	// The frames channel belongs to the latest call to Start or Run, which
+	// closes it when done. Calling either again after that opens a new one.
+	frames       chan Frame
+	framesOwner  uint64
+	framesClosed bool
+}
+
+// NewAnimator creates a new animator that ticks at the given number of frames
+// per second.
+func NewAnimator(fps int) *Animator {
+	if fps < 1 {
+		fps = 1
+	}
+	return &Animator{
+		interval: time.Second / time.Duration(fps),
+		group:    NewGroup(FPS(fps)),
+		frames:   make(chan Frame, 1),
+	}
+}
+
+// AddSpring adds a value animated by a spring with the given angular frequency
+// and damping ratio, as for NewSpring, starting at rest at the given position.
+// It returns the index of the spring.
+func (a *Animator) AddSpring(angularFrequency, dampingRatio, pos float64) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.springs = append(a.springs, a.group.AddSpring(angularFrequency, dampingRatio, pos))
+	return len(a.springs) - 1
+}
+
+// SetTarget sets the value a spring moves towards. If the animator was started
+// with Start and has settled, it starts ticking again.
+func (a *Animator) SetTarget(spring int, target float64) {
+	a.mu.Lock()
+	if spring < 0 || spring >= len(a.springs) {
+		a.mu.Unlock()
+		return
+	}
+	a.springs[spring].SetTarget(target)
+	a.mu.Unlock()
+
+	a.resume()
+}
+
+// Spring returns the current position and velocity of a spring.
+func (a *Animator) Spring(spring int) (pos, vel float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if spring < 0 || spring >= len(a.springs) {
+		return 0, 0
+	}
+	s := a.springs[spring]
+	return s.Position(), s.Velocity()
+}
+
+// AddProjectile adds a projectile to animate and returns its index. It keeps
+// moving until done reports true, after which it's no longer updated. If done
+// is nil, it keeps moving until the animator's context is cancelled.
+//
+// The animator takes ownership of the projectile, which shouldn't be accessed
+// elsewhere while the animator is running. Read its position from frames.
+// The projectile should be initialized with the same time delta as the
+// animator, FPS(fps).
+func (a *Animator) AddProjectile(p *Projectile, done func(*Projectile) bool) int {
+	a.mu.Lock()
+	a.projectiles = append(a.projectiles, a.group.AddProjectile(p, done))
+	i := len(a.projectiles) - 1
+	a.mu.Unlock()
+
+	a.resume()
+	return i
+}
+
+// SetThreshold sets how close to its target, and how slow, a spring has to be
+// to count as settled. It defaults to DefaultThreshold.
+func (a *Animator) SetThreshold(threshold float64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.group.SetThreshold(threshold)
+}
+
+// OnFrame sets a function to call with each frame. It's called on the
+// animator's goroutine, so it should return quickly.
+func (a *Animator) OnFrame(f func(Frame)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.onFrame = f
+}
+
+// Frames returns a channel that receives frames. Only the latest frame is
+// kept: if a frame isn't received before the next one is ready, it's dropped,
+// so a slow reader never holds up the animation.
+//
+// The channel is closed when the context passed to Start is done, or when Run
+// returns. Starting the animator again after that opens a new channel, so call
+// Frames again to receive from it.
+func (a *Animator) Frames() <-chan Frame {
+	a.publishMu.Lock()
+	defer a.publishMu.Unlock()
+	return a.frames
+}
+
+// Running returns whether the animator is ticking.
+func (a *Animator) Running() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.running
+}
+
+// Settled returns whether every spring is at rest at its target and every
+// projectile is done.
+func (a *Animator) Settled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.group.Settled()
+}
+
+// Start runs the animator on a new goroutine until the context is cancelled.
+// It stops ticking whenever everything settles, and starts again when
+// a target changes or a projectile is added.
+func (a *Animator) Start(ctx context.Context) {
+	a.mu.Lock()
+	a.ctx = ctx
+	a.mu.Unlock()
+
+	owner := a.openFrames()
+	go func() {
+		<-ctx.Done()
+		a.closeFrames(owner)
+	}()
+
+	a.resume()
+}
+
+// Run runs the animator on the calling goroutine until everything settles,
+// returning nil, or the context is cancelled, returning its error.
+func (a *Animator) Run(ctx context.Context) error {
+	a.mu.Lock()
+	if a.running {
+		a.mu.Unlock()
+		return ErrAnimatorRunning
+	}
+	settled := a.group.Settled()
+	a.running = !settled
+	a.mu.Unlock()
+
+	defer a.closeFrames(a.openFrames())
+	if settled {
+		return nil
+	}
+	return a.run(ctx)
+}
+
+// resume starts ticking on a new goroutine if the animator was started, isn't
+// running and has something to animate.
+func (a *Animator) resume() {
+	a.mu.Lock()
+	ctx := a.ctx
+	if a.running || ctx == nil || ctx.Err() != nil || a.group.Settled() {
+		a.mu.Unlock()
+		return
+	}
+	a.running = true
+	a.mu.Unlock()
+
+	go a.run(ctx)
+}
+
+// run ticks until everything settles or the context is cancelled. The caller
+// must have set running.
+func (a *Animator) run(ctx context.Context) error {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			a.mu.Lock()
+			a.running = false
+			a.mu.Unlock()
+			return ctx.Err()
+		case now = <-ticker.C:
+		}
+
+		a.mu.Lock()
+		frame := a.step(now)
+		onFrame := a.onFrame
+		if frame.Settled {
+			a.running = false
+		}
+		a.seq++
+		seq := a.seq
+		a.mu.Unlock()
+
+		a.publish(frame, seq, onFrame)
+		if frame.Settled {
+			return nil
+		}
+	}
+}
+
+// step advances everything by one time step and returns the resulting frame.
+// The caller must hold the lock.
+func (a *Animator) step(now time.Time) Frame {
+	frame := Frame{
+		Time:       now,
+		Positions:  make([]float64, len(a.springs)),
+		Velocities: make([]float64, len(a.springs)),
+		Points:     make([]Point, len(a.projectiles)),
+		Settled:    a.group.Update(),
+	}
+	for i, s := range a.springs {
+		frame.Positions[i], frame.Velocities[i] = s.Position(), s.Velocity()
+	}
+	for i, p := range a.projectiles {
+		frame.Points[i] = p.Position()
+	}
+	return frame
+}
+
+// publish sends a frame to the callback and the channel, replacing any frame
+// still waiting in the channel. Frames older than the last one published are
+// dropped.
+func (a *Animator) publish(frame Frame, seq uint64, onFrame func(Frame)) {
+	a.publishMu.Lock()
+	if seq < a.published {
+		a.publishMu.Unlock()
+		return
+	}
+	a.published = seq
+	a.publishMu.Unlock()
+
+	// The callback runs without any lock held, so it can call back into the
+	// animator, including Frames.
+	if onFrame != nil {
+		onFrame(frame)
+	}
+
+	// Sending doesn't block, so it's done under the lock, where the channel
+	// can't be closed or replaced underneath it. A newer frame may have been
+	// published during the callback, in which case this one is stale.
+	a.publishMu.Lock()
+	defer a.publishMu.Unlock()
+	if seq < a.published || a.framesClosed {
+		return
+	}
+	for {
+		select {
+		case a.frames <- frame:
+			return
+		default:
+		}
+		select {
+		case <-a.frames:
+		default:
+		}
+	}
+}
+
+// openFrames makes the caller the owner of the frames channel, opening a new
+// one if it was closed, and returns the caller's ownership token.
+func (a *Animator) openFrames() uint64 {
+	a.publishMu.Lock()
+	defer a.publishMu.Unlock()
+	if a.framesClosed {
+		a.frames = make(chan Frame, 1)
+		a.framesClosed = false
+	}
+	a.framesOwner++
+	return a.framesOwner
+}
+
+// closeFrames closes the frames channel, unless ownership has passed to
+// a later call to Start or Run.
+func (a *Animator) closeFrames(owner uint64) {
+	a.publishMu.Lock()
+	defer a.publishMu.Unlock()
+	if owner == a.framesOwner && !a.framesClosed {
+		close(a.frames)
+		a.framesClosed = true
+	}
+}
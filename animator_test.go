@@ -0,0 +1,185 @@
+package harmonica_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestAnimatorRun(t *testing.T) {
+	a := NewAnimator(500)
+	x := a.AddSpring(40, 1, 0)
+	a.SetTarget(x, 10)
+
+	var (
+		mu     sync.Mutex
+		frames int
+		last   Frame
+	)
+	a.OnFrame(func(f Frame) {
+		mu.Lock()
+		frames++
+		last = f
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if frames < 10 || !last.Settled || last.Positions[x] != 10 {
+		t.Fatalf("expected spring to settle at 10, got %+v after %d frames", last, frames)
+	}
+	if pos, vel := a.Spring(x); pos != 10 || vel != 0 || a.Running() {
+		t.Fatalf("expected animator to stop at rest, got %.2f, %.2f", pos, vel)
+	}
+
+	// With nothing to do, Run returns immediately.
+	if err := a.Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAnimatorRetarget(t *testing.T) {
+	a := NewAnimator(500)
+	x := a.AddSpring(40, 1, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.Start(ctx)
+
+	// Retarget concurrently, then wait for the final frame.
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a.SetTarget(x, float64(i))
+		}(i)
+	}
+	wg.Wait()
+	a.SetTarget(x, 20)
+
+	for {
+		select {
+		case f := <-a.Frames():
+			if f.Settled && f.Positions[x] == 20 {
+				return
+			}
+		case <-ctx.Done():
+			t.Fatal("animator never settled at the final target")
+		}
+	}
+}
+
+func TestAnimatorCancel(t *testing.T) {
+	a := NewAnimator(500)
+	a.AddProjectile(NewProjectile(FPS(500), Point{}, Vector{1, 0, 0}, Vector{}), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- a.Run(ctx) }()
+
+	f := <-a.Frames()
+	if f.Settled || len(f.Points) != 1 {
+		t.Fatalf("unexpected frame %+v", f)
+	}
+	if err := a.Run(ctx); !errors.Is(err, ErrAnimatorRunning) {
+		t.Fatalf("expected ErrAnimatorRunning, got %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("animator didn't stop when cancelled")
+	}
+}
+
+// drain ranges over frames until the channel is closed, returning the last
+// frame received.
+func drain(t *testing.T, frames <-chan Frame) Frame {
+	done := make(chan Frame)
+	go func() {
+		var last Frame
+		for f := range frames {
+			last = f
+		}
+		done <- last
+	}()
+	select {
+	case last := <-done:
+		return last
+	case <-time.After(5 * time.Second):
+		t.Fatal("frames channel was never closed")
+	}
+	return Frame{}
+}
+
+func TestAnimatorFramesClosed(t *testing.T) {
+	a := NewAnimator(500)
+	x := a.AddSpring(40, 1, 0)
+	a.SetTarget(x, 10)
+
+	// Ranging over frames ends once Run returns.
+	frames := a.Frames()
+	go a.Run(context.Background())
+	if last := drain(t, frames); !last.Settled || last.Positions[x] != 10 {
+		t.Fatalf("expected the last frame to be settled at 10, got %+v", last)
+	}
+
+	// And once the context passed to Start is cancelled, with a new channel
+	// for the new run.
+	ctx, cancel := context.WithCancel(context.Background())
+	a.Start(ctx)
+	frames = a.Frames()
+	a.SetTarget(x, 20)
+	if f := <-frames; f.Positions == nil {
+		t.Fatal("expected frames from the new run")
+	}
+	cancel()
+	drain(t, frames)
+}
+
+func TestAnimatorCallbackReentry(t *testing.T) {
+	a := NewAnimator(500)
+	x := a.AddSpring(40, 1, 0)
+	a.SetTarget(x, 10)
+
+	// Calling back into the animator from a callback doesn't deadlock.
+	var retargeted bool
+	a.OnFrame(func(f Frame) {
+		if a.Frames() == nil {
+			t.Error("expected a frames channel")
+		}
+		if !retargeted {
+			retargeted = true
+			a.SetTarget(x, 20)
+		}
+	})
+
+	done := make(chan error)
+	go func() { done <- a.Run(context.Background()) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("animator deadlocked calling back from a callback")
+	}
+	if pos, _ := a.Spring(x); pos != 20 {
+		t.Fatalf("expected spring to settle at the new target, got %.2f", pos)
+	}
+}
@@ -0,0 +1,351 @@
+package harmonica
+
+// This file defines baked tables, which record motion ahead of time so it can
+// be played back without any trigonometry or exponentials, just a lookup and
+// an interpolation. They're handy for running many identical animations at
+// once, or on small devices where every cycle counts.
+//
+// Example usage:
+//
+//    // Run once to bake a spring's motion into 30 samples.
+//    table := BakeSpring(NewSpring(FPS(60), 6.0, 0.3), 0.001, 30)
+//
+//    // Update on every frame.
+//    elapsed := 0.0
+//    someUpdateLoop(func() {
+//        elapsed += FPS(60)
+//        pos := from + (to-from)*table.Value(elapsed)
+//    })
+//
+// Tables store samples as 32-bit floats and can be saved with MarshalBinary,
+// so they can be baked on a fast machine and shipped to a slow one.
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// tableMagic identifies binary encoded tables.
+const tableMagic = "HMTB"
+
+// tableVersion is the version of the binary table encoding.
+const tableVersion = 1
+
+// tableHeaderSize is the size of an encoded table before its samples: magic,
+// version, interpolation, channels, frames, duration and two error bounds.
+const tableHeaderSize = 4 + 1 + 1 + 2 + 4 + 8*3
+
+// ErrInvalidTable is returned when decoding malformed binary table data.
+var ErrInvalidTable = errors.New("harmonica: invalid table data")
+
+// Interpolation is how a table fills in values between its samples.
+type Interpolation uint8
+
+// Available interpolations.
+const (
+	// InterpolateLinear draws straight lines between samples. It's the
+	// cheapest, but needs more samples to follow curves closely.
+	InterpolateLinear Interpolation = iota
+
+	// InterpolateCubic draws smooth Catmull-Rom curves through samples, so
+	// fewer samples are needed.
+	InterpolateCubic
+)
+
+// Table is motion sampled at evenly spaced times, with one or more values,
+// or channels, per sample.
+type Table struct {
+	duration      float64
+	channels      int
+	frames        int
+	samples       []float32 // frames × channels, interleaved
+	interpolation Interpolation
+	errLinear     float64
+	errCubic      float64
+}
+
+// Bake samples motion into a table. It accepts the duration of the motion in
+// seconds, the number of samples to take, which must be at least 2, the
+// number of channels per sample, and a function that writes the value of each
+// channel at a given time into out.
+//
+// The function is called with times in increasing order from 0 to the
+// duration, and additionally between samples to measure the table's error.
+func Bake(duration float64, frames, channels int, f func(t float64, out []float64)) *Table {
+	if frames < 2 {
+		frames = 2
+	}
+	if channels < 1 {
+		channels = 1
+	}
+
+	t := &Table{
+		duration: math.Max(0, duration),
+		channels: channels,
+		frames:   frames,
+		samples:  make([]float32, frames*channels),
+	}
+
+	// Take the samples, and remember the midpoints between them to measure
+	// the error later.
+	var (
+		out  = make([]float64, channels)
+		mids = make([]float64, (frames-1)*channels)
+	)
+	for i := 0; i < frames; i++ {
+		f(t.frameTime(float64(i)), out)
+		for c, v := range out {
+			t.samples[i*channels+c] = float32(v)
+		}
+		if i < frames-1 {
+			f(t.frameTime(float64(i)+0.5), out)
+			copy(mids[i*channels:], out)
+		}
+	}
+
+	// Measure the error of both interpolations, so it can be reported
+	// whichever is used.
+	for _, interp := range []Interpolation{InterpolateLinear, InterpolateCubic} {
+		t.interpolation = interp
+		var worst float64
+		for i := 0; i < frames-1; i++ {
+			t.Sample(t.frameTime(float64(i)+0.5), out)
+			for c, v := range out {
+				worst = math.Max(worst, math.Abs(v-mids[i*channels+c]))
+			}
+		}
+		if interp == InterpolateLinear {
+			t.errLinear = worst
+		} else {
+			t.errCubic = worst
+		}
+	}
+	t.interpolation = InterpolateLinear
+
+	return t
+}
+
+// BakeSpring bakes a spring's motion from 0 to 1, including any overshoot,
+// until it settles within the tolerance, into a single channel table with the
+// given number of samples.
+func BakeSpring(s Spring, tolerance float64, frames int) *Table {
+	duration := s.Duration(tolerance).Seconds()
+	return Bake(duration, frames, 1, func(t float64, out []float64) {
+		if t >= duration {
+			out[0] = 1
+			return
+		}
+		out[0], _ = NewSpring(t, s.angularFrequency, s.dampingRatio).Update(0, 0, 1)
+	})
+}
+
+// BakeTween bakes a tween's values into a single channel table with the given
+// number of samples, covering its delay and all its plays. A tween that
+// repeats forever is baked for one cycle, which is two plays if it yoyos.
+func BakeTween(tw *Tween, frames int) *Table {
+	plays := tw.repeat + 1
+	if tw.repeat == RepeatForever {
+		plays = 1
+		if tw.yoyo {
+			plays = 2
+		}
+	}
+	duration := tw.delay + tw.duration*float64(plays)
+	return Bake(duration, frames, 1, func(t float64, out []float64) {
+		out[0] = tw.ValueAt(t)
+	})
+}
+
+// BakeProjectile bakes the path of a projectile over the given duration into
+// a table with three channels, X, Y and Z, and the given number of samples.
+// The projectile is simulated with its own time step, forces included, and
+// isn't changed.
+func BakeProjectile(p *Projectile, duration float64, frames int) *Table {
+	var (
+		sim     = p.Clone()
+		elapsed float64
+	)
+	return Bake(duration, frames, 3, func(t float64, out []float64) {
+		// Step the simulation up to the requested time, then take a final
+		// partial step on a copy so it can continue from where it was.
+		for sim.deltaTime > 0 && elapsed+sim.deltaTime <= t+epsilon {
+			sim.step(sim.deltaTime, nil)
+			elapsed += sim.deltaTime
+		}
+		pos := sim.pos
+		if rem := t - elapsed; rem > epsilon {
+			pos = sim.Clone().step(rem, nil)
+		}
+		out[0], out[1], out[2] = pos.X, pos.Y, pos.Z
+	})
+}
+
+// frameTime returns the time of a sample, which may be fractional.
+func (t *Table) frameTime(frame float64) float64 {
+	return t.duration * frame / float64(t.frames-1)
+}
+
+// Duration returns the duration of the baked motion in seconds.
+func (t *Table) Duration() float64 {
+	return t.duration
+}
+
+// Channels returns the number of values per sample.
+func (t *Table) Channels() int {
+	return t.channels
+}
+
+// Len returns the number of samples.
+func (t *Table) Len() int {
+	return t.frames
+}
+
+// Interpolation returns how the table fills in values between samples.
+func (t *Table) Interpolation() Interpolation {
+	return t.interpolation
+}
+
+// SetInterpolation sets how the table fills in values between samples.
+func (t *Table) SetInterpolation(interp Interpolation) {
+	if interp == InterpolateLinear || interp == InterpolateCubic {
+		t.interpolation = interp
+	}
+}
+
+// MaxError returns the largest difference between the table and the motion it
+// was baked from, as measured half way between samples with the current
+// interpolation. It includes the error from storing samples as 32-bit floats.
+func (t *Table) MaxError() float64 {
+	if t.interpolation == InterpolateCubic {
+		return t.errCubic
+	}
+	return t.errLinear
+}
+
+// Sample writes the value of each channel at the given time into out, which
+// must have room for every channel, and returns it. Times before the start or
+// after the end hold the first or last sample. An empty table, such as the
+// zero value, holds zero.
+func (t *Table) Sample(time float64, out []float64) []float64 {
+	if t.frames < 2 {
+		for c := 0; c < t.channels; c++ {
+			out[c] = 0
+		}
+		return out
+	}
+
+	var (
+		last = t.frames - 1
+		x    float64
+	)
+	if t.duration > 0 {
+		x = math.Max(0, math.Min(float64(last), time/t.duration*float64(last)))
+	} else if time > 0 {
+		x = float64(last)
+	}
+
+	i := int(x)
+	if i >= last {
+		i = last - 1
+	}
+	u := x - float64(i)
+
+	for c := 0; c < t.channels; c++ {
+		if t.interpolation == InterpolateCubic {
+			out[c] = catmullRom(t.at(i-1, c), t.at(i, c), t.at(i+1, c), t.at(i+2, c), u)
+		} else {
+			out[c] = lerp(t.at(i, c), t.at(i+1, c), u)
+		}
+	}
+	return out
+}
+
+// Value returns the value of the first channel at the given time.
+func (t *Table) Value(time float64) float64 {
+	var out [1]float64
+	if t.channels <= 1 {
+		return t.Sample(time, out[:])[0]
+	}
+	return t.Sample(time, make([]float64, t.channels))[0]
+}
+
+// Point returns the first three channels at the given time as a point, as
+// baked by BakeProjectile.
+func (t *Table) Point(time float64) Point {
+	var out [3]float64
+	if t.channels == 3 {
+		t.Sample(time, out[:])
+	} else {
+		copy(out[:], t.Sample(time, make([]float64, t.channels)))
+	}
+	return Point{out[0], out[1], out[2]}
+}
+
+// at returns a sample, clamping the frame to the table.
+func (t *Table) at(frame, channel int) float64 {
+	if frame < 0 {
+		frame = 0
+	} else if frame >= t.frames {
+		frame = t.frames - 1
+	}
+	return float64(t.samples[frame*t.channels+channel])
+}
+
+// catmullRom interpolates between b and c with a Catmull-Rom spline, using a
+// and d to shape the curve.
+func catmullRom(a, b, c, d, u float64) float64 {
+	return b + 0.5*u*(c-a+u*(2*a-5*b+4*c-d+u*(3*(b-c)+d-a)))
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (t *Table) MarshalBinary() ([]byte, error) {
+	b := make([]byte, tableHeaderSize+4*len(t.samples))
+	copy(b, tableMagic)
+	b[4] = tableVersion
+	b[5] = byte(t.interpolation)
+	binary.LittleEndian.PutUint16(b[6:], uint16(t.channels))
+	binary.LittleEndian.PutUint32(b[8:], uint32(t.frames))
+	binary.LittleEndian.PutUint64(b[12:], math.Float64bits(t.duration))
+	binary.LittleEndian.PutUint64(b[20:], math.Float64bits(t.errLinear))
+	binary.LittleEndian.PutUint64(b[28:], math.Float64bits(t.errCubic))
+	for i, v := range t.samples {
+		binary.LittleEndian.PutUint32(b[tableHeaderSize+4*i:], math.Float32bits(v))
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (t *Table) UnmarshalBinary(data []byte) error {
+	if len(data) < tableHeaderSize || string(data[:4]) != tableMagic || data[4] != tableVersion {
+		return ErrInvalidTable
+	}
+
+	var (
+		interp   = Interpolation(data[5])
+		channels = int(binary.LittleEndian.Uint16(data[6:]))
+		frames   = int(binary.LittleEndian.Uint32(data[8:]))
+		duration = math.Float64frombits(binary.LittleEndian.Uint64(data[12:]))
+	)
+	if interp > InterpolateCubic || channels < 1 || frames < 2 ||
+		len(data) != tableHeaderSize+4*channels*frames ||
+		math.IsNaN(duration) || duration < 0 {
+		return ErrInvalidTable
+	}
+
+	samples := make([]float32, channels*frames)
+	for i := range samples {
+		samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[tableHeaderSize+4*i:]))
+	}
+
+	*t = Table{
+		duration:      duration,
+		channels:      channels,
+		frames:        frames,
+		samples:       samples,
+		interpolation: interp,
+		errLinear:     math.Float64frombits(binary.LittleEndian.Uint64(data[20:])),
+		errCubic:      math.Float64frombits(binary.LittleEndian.Uint64(data[28:])),
+	}
+	return nil
+}
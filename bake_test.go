@@ -0,0 +1,131 @@
+package harmonica_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestBakeSpring(t *testing.T) {
+	s := NewSpring(FPS(fps), 6, 0.3)
+	table := BakeSpring(s, 0.001, 40)
+
+	if table.Len() != 40 || table.Channels() != 1 {
+		t.Fatalf("unexpected table shape: %d samples of %d channels", table.Len(), table.Channels())
+	}
+	if !equal(table.Duration(), s.Duration(0.001).Seconds()) {
+		t.Fatalf("unexpected duration %.3f", table.Duration())
+	}
+
+	// Playback follows the spring within the reported error.
+	for _, interp := range []Interpolation{InterpolateLinear, InterpolateCubic} {
+		table.SetInterpolation(interp)
+		bound := table.MaxError() + 1e-3
+
+		var pos, vel, elapsed float64
+		for elapsed < table.Duration() {
+			pos, vel = s.Update(pos, vel, 1)
+			elapsed += FPS(fps)
+			if got := table.Value(elapsed); math.Abs(got-pos) > bound {
+				t.Logf("Want: %.4f", pos)
+				t.Logf("Got:  %.4f", got)
+				t.Fatalf("playback with interpolation %d diverged at %.3fs", interp, elapsed)
+			}
+		}
+	}
+
+	table.SetInterpolation(InterpolateLinear)
+	linear := table.MaxError()
+	table.SetInterpolation(InterpolateCubic)
+	if cubic := table.MaxError(); cubic >= linear {
+		t.Fatalf("expected cubic interpolation to be more accurate: %.5f vs %.5f", cubic, linear)
+	}
+
+	if table.Value(-1) != 0 || table.Value(table.Duration()+1) != 1 {
+		t.Fatal("expected playback to hold the ends")
+	}
+}
+
+func TestBakeProjectile(t *testing.T) {
+	p := NewProjectile(FPS(fps), Point{0, 10, 0}, Vector{5, 10, 0}, Gravity)
+	table := BakeProjectile(p, 1, fps+1)
+
+	// Sampled once per frame, the table reproduces the simulation exactly,
+	// apart from rounding to 32-bit floats.
+	for i := 1; i <= fps; i++ {
+		want := p.Update()
+		got := table.Point(float64(i) / fps)
+		if got.Distance(want) > 1e-4 {
+			t.Logf("Want: %+v", want)
+			t.Logf("Got:  %+v", got)
+			t.Fatalf("playback diverged on frame %d", i)
+		}
+	}
+}
+
+func TestBakeTween(t *testing.T) {
+	tw := NewTween(FPS(fps), 0, 10, 1, EaseInOutCubic)
+	tw.SetRepeat(1)
+	tw.SetYoyo(true)
+
+	table := BakeTween(tw, 64)
+	table.SetInterpolation(InterpolateCubic)
+	if !equal(table.Duration(), 2) {
+		t.Fatalf("expected two plays to take 2s, got %.2f", table.Duration())
+	}
+	for _, x := range []float64{0.25, 0.5, 1, 1.5} {
+		if got, want := table.Value(x), tw.ValueAt(x); math.Abs(got-want) > table.MaxError()+1e-6 {
+			t.Fatalf("at %.2fs: got %.4f, want %.4f", x, got, want)
+		}
+	}
+	if table.MaxError() > 0.01 {
+		t.Fatalf("unexpectedly large error %.4f", table.MaxError())
+	}
+}
+
+func TestTableBinary(t *testing.T) {
+	table := BakeSpring(NewSpring(FPS(fps), 8, 0.5), 0.001, 32)
+	table.SetInterpolation(InterpolateCubic)
+
+	b, err := table.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) > 200 {
+		t.Fatalf("expected a compact encoding, got %d bytes", len(b))
+	}
+
+	var got Table
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != table.Len() || got.Interpolation() != InterpolateCubic || got.MaxError() != table.MaxError() {
+		t.Fatal("decoded table differs")
+	}
+	for x := 0.0; x < table.Duration(); x += 0.05 {
+		if got.Value(x) != table.Value(x) {
+			t.Fatalf("decoded table differs at %.2fs", x)
+		}
+	}
+
+	for _, bad := range [][]byte{nil, b[:len(b)-1], append([]byte("XXXX"), b[4:]...)} {
+		if err := got.UnmarshalBinary(bad); !errors.Is(err, ErrInvalidTable) {
+			t.Fatalf("expected ErrInvalidTable, got %v", err)
+		}
+	}
+}
+
+func TestTableZeroValue(t *testing.T) {
+	var table Table
+	for _, interp := range []Interpolation{InterpolateLinear, InterpolateCubic} {
+		table.SetInterpolation(interp)
+		if v := table.Value(0.5); v != 0 {
+			t.Fatalf("expected an empty table to hold 0, got %.4f", v)
+		}
+		if p := table.Point(0.5); p != (Point{}) {
+			t.Fatalf("expected an empty table to hold the origin, got %v", p)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package harmonica
+
+// This file defines collisions between projectiles, which are treated as
+// circles in 2D or spheres in 3D. A spatial hash quickly rules out projectiles
+// that are far apart, then overlapping pairs are pushed apart and bounce off
+// each other.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    collisions := NewCollisions(0, 0.8)
+//    collisions.OnCollide = func(c Collision) {
+//        playSound(c.Impulse)
+//    }
+//
+//    colliders := []Collider{
+//        {Projectile: a, Radius: 1},
+//        {Projectile: b, Radius: 2},
+//    }
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        a.Update()
+//        b.Update()
+//        collisions.Resolve(colliders)
+//    })
+//
+// For background on the collision response see:
+// https://en.wikipedia.org/wiki/Collision_response#Impulse-based_contact_model
+
+import "math"
+
+// Collider is a projectile with a radius, for the purposes of collision.
+type Collider struct {
+	Projectile *Projectile
+	Radius     float64
+}
+
+// Collision describes a collision between two colliders.
+type Collision struct {
+	// A and B are the indices of the colliding colliders, with A < B.
+	A, B int
+
+	// Point is the point of contact.
+	Point Point
+
+	// Normal is the unit vector pointing from A to B.
+	Normal Vector
+
+	// Depth is how far the colliders overlapped before they were separated.
+	Depth float64
+
+	// Impulse is the magnitude of the impulse applied to separate the
+	// colliders. It's zero when they were already moving apart.
+	Impulse float64
+}
+
+// Collisions detects and resolves collisions between colliders.
+type Collisions struct {
+	// CellSize is the size of the cells in the spatial hash. It should be at
+	// least the diameter of the largest collider; zero picks it automatically
+	// based on the largest collider.
+	CellSize float64
+
+	// Restitution is the bounciness of collisions, from 0 for perfectly
+	// inelastic collisions, where colliders stop moving apart, to 1 for
+	// perfectly elastic collisions, where no energy is lost.
+	Restitution float64
+
+	// OnCollide, if set, is called for every collision, after it's been
+	// resolved.
+	OnCollide func(Collision)
+
+	cells     map[cell][]int
+	colliders []Collider // scratch space for ResolveParticles
+}
+
+// cell is a cell in the spatial hash.
+type cell struct {
+	x, y, z int
+}
+
+// NewCollisions creates a collision solver with the given cell size and
+// restitution. See Collisions for details.
+func NewCollisions(cellSize, restitution float64) *Collisions {
+	return &Collisions{
+		CellSize:    cellSize,
+		Restitution: restitution,
+		cells:       make(map[cell][]int),
+	}
+}
+
+// Resolve finds all pairs of overlapping colliders, pushes them apart and
+// applies impulses so they bounce off each other. Call it after updating the
+// projectiles.
+//
+// Each pair is resolved once, in order, so when many colliders overlap at
+// once the result may not be perfectly resolved until later frames.
+func (c *Collisions) Resolve(colliders []Collider) {
+	if c.cells == nil {
+		c.cells = make(map[cell][]int)
+	}
+
+	size := c.CellSize
+	if size <= 0 {
+		for _, col := range colliders {
+			size = math.Max(size, 2*col.Radius)
+		}
+	}
+	if size <= 0 {
+		return
+	}
+
+	// Reuse the cell slices from the previous frame to avoid allocating, and
+	// forget cells which have gone unused so the map doesn't grow forever.
+	for k, v := range c.cells {
+		if len(v) == 0 {
+			delete(c.cells, k)
+			continue
+		}
+		c.cells[k] = v[:0]
+	}
+
+	for i, col := range colliders {
+		k := cellOf(col.Projectile.pos, size)
+		c.cells[k] = append(c.cells[k], i)
+	}
+
+	for i, col := range colliders {
+		k := cellOf(col.Projectile.pos, size)
+
+		// A collider can only overlap colliders in its own or neighboring
+		// cells, as long as cells are at least as big as colliders.
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dz := -1; dz <= 1; dz++ {
+					for _, j := range c.cells[cell{k.x + dx, k.y + dy, k.z + dz}] {
+						if j > i {
+							c.collide(colliders, i, j)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// ResolveParticles resolves collisions between the live particles in
+// a particle system, treating them all as having the given radius. Collision
+// indices refer to the particles as returned by ParticleSystem.Particles.
+func (c *Collisions) ResolveParticles(s *ParticleSystem, radius float64) {
+	c.colliders = c.colliders[:0]
+	for i := 0; i < s.live; i++ {
+		c.colliders = append(c.colliders, Collider{
+			Projectile: &s.particles[i].Projectile,
+			Radius:     radius,
+		})
+	}
+	c.Resolve(c.colliders)
+}
+
+// cellOf returns the spatial hash cell containing the given point.
+func cellOf(p Point, size float64) cell {
+	return cell{
+		x: int(math.Floor(p.X / size)),
+		y: int(math.Floor(p.Y / size)),
+		z: int(math.Floor(p.Z / size)),
+	}
+}
+
+// collide resolves a collision between two colliders if they overlap.
+func (c *Collisions) collide(colliders []Collider, i, j int) {
+	var (
+		a = colliders[i].Projectile
+		b = colliders[j].Projectile
+
+		minDist = colliders[i].Radius + colliders[j].Radius
+		d       = b.pos.Sub(a.pos)
+		distSq  = d.LengthSquared()
+	)
+
+	if distSq >= minDist*minDist {
+		return
+	}
+
+	dist := math.Sqrt(distSq)
+	normal := Vector{1, 0, 0}
+	if dist > epsilon {
+		normal = d.Scale(1 / dist)
+	}
+
+	var (
+		depth = minDist - dist
+		invA  = a.inverseMass()
+		invB  = b.inverseMass()
+		inv   = invA + invB
+	)
+
+	// Push the colliders apart in inverse proportion to their masses so that
+	// heavy colliders barely budge.
+	a.pos = a.pos.Add(normal.Scale(-depth * invA / inv))
+	b.pos = b.pos.Add(normal.Scale(depth * invB / inv))
+
+	// Only bounce if the colliders are moving towards each other.
+	var impulse float64
+	if vn := b.vel.Sub(a.vel).Dot(normal); vn < 0 {
+		impulse = -(1 + c.Restitution) * vn / inv
+		a.ApplyImpulse(normal.Scale(-impulse))
+		b.ApplyImpulse(normal.Scale(impulse))
+	}
+
+	if c.OnCollide != nil {
+		c.OnCollide(Collision{
+			A:       i,
+			B:       j,
+			Point:   a.pos.Add(normal.Scale(colliders[i].Radius)),
+			Normal:  normal,
+			Depth:   depth,
+			Impulse: impulse,
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestElasticCollision(t *testing.T) {
+	a := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{5, 0, 0}, Vector{})
+	b := NewProjectile(FPS(fps), Point{3, 0, 0}, Vector{-5, 0, 0}, Vector{})
+	colliders := []Collider{{a, 1}, {b, 1}}
+
+	var hits []Collision
+	collisions := NewCollisions(0, 1)
+	collisions.OnCollide = func(c Collision) {
+		hits = append(hits, c)
+	}
+
+	for i := 0; i < fps; i++ {
+		a.Update()
+		b.Update()
+		collisions.Resolve(colliders)
+	}
+
+	if len(hits) != 1 {
+		t.Fatalf("expected one collision, got %d", len(hits))
+	}
+	if hits[0].A != 0 || hits[0].B != 1 || !equal(hits[0].Normal.X, 1) || !equal(hits[0].Impulse, 10) {
+		t.Fatalf("collision unexpected: %+v", hits[0])
+	}
+
+	// Equal masses swap velocities in an elastic collision.
+	if va, vb := a.Velocity(), b.Velocity(); !equal(va.X, -5) || !equal(vb.X, 5) {
+		t.Logf("Want: (%.2f, %.2f)", -5.0, 5.0)
+		t.Logf("Got:  (%.2f, %.2f)", va.X, vb.X)
+		t.Fatal("velocities unexpected")
+	}
+}
+
+func TestInelasticCollision(t *testing.T) {
+	a := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{6, 0, 0}, Vector{})
+	b := NewProjectile(FPS(fps), Point{1.5, 0, 0}, Vector{0, 0, 0}, Vector{})
+	b.SetMass(2)
+
+	collisions := NewCollisions(4, 0)
+	collisions.Resolve([]Collider{{a, 1}, {b, 1}})
+
+	// Momentum is conserved and the colliders move together.
+	if va, vb := a.Velocity(), b.Velocity(); !equal(va.X, 2) || !equal(vb.X, 2) {
+		t.Logf("Want: (%.2f, %.2f)", 2.0, 2.0)
+		t.Logf("Got:  (%.2f, %.2f)", va.X, vb.X)
+		t.Fatal("velocities unexpected")
+	}
+
+	// And they no longer overlap.
+	if d := a.Position().Distance(b.Position()); !equal(d, 2) {
+		t.Logf("Want: %.2f", 2.0)
+		t.Logf("Got:  %.2f", d)
+		t.Fatal("separation unexpected")
+	}
+}
+
+func TestCollisionBroadphase(t *testing.T) {
+	var colliders []Collider
+	for i := 0; i < 50; i++ {
+		p := NewProjectile(FPS(fps), Point{float64(i) * 3, 0, 0}, Vector{}, Vector{})
+		colliders = append(colliders, Collider{p, 1})
+	}
+
+	var n int
+	collisions := NewCollisions(0, 1)
+	collisions.OnCollide = func(Collision) { n++ }
+	collisions.Resolve(colliders)
+	if n != 0 {
+		t.Fatalf("expected no collisions, got %d", n)
+	}
+
+	// Across cell boundaries, too.
+	colliders[10].Projectile.SetPosition(Point{31.5, 0, 0})
+	collisions.Resolve(colliders)
+	if n != 1 {
+		t.Fatalf("expected one collision, got %d", n)
+	}
+}
+
+func TestParticleCollisions(t *testing.T) {
+	s := NewParticleSystem(20, 1)
+	s.AddEmitter(&Emitter{
+		Direction:   Vector{1, 0, 0},
+		Spread:      math.Pi,
+		MinSpeed:    1,
+		MaxSpeed:    2,
+		MinLifetime: 10,
+		MaxLifetime: 10,
+		Burst:       20,
+		Planar:      true,
+	})
+
+	// Every particle starts in the same spot, so they should all be pushed
+	// apart as they fly outwards.
+	collisions := NewCollisions(0, 0.5)
+	for i := 0; i < fps; i++ {
+		s.Update(FPS(fps))
+		collisions.ResolveParticles(s, 0.25)
+	}
+
+	ps := s.Particles()
+	for i := range ps {
+		for j := i + 1; j < len(ps); j++ {
+			if d := ps[i].Position().Distance(ps[j].Position()); d < 0.45 {
+				t.Fatalf("particles %d and %d still overlap at distance %.2f", i, j, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package harmonica
+
+// This file defines CSS timing functions, so animations in the terminal can
+// share easing definitions with the web. ParseTimingFunction understands the
+// same strings as the CSS animation-timing-function and
+// transition-timing-function properties, and returns an easing function that
+// can be used anywhere one's accepted, such as in a Tween.
+//
+// Example usage:
+//
+//    ease, err := ParseTimingFunction("cubic-bezier(.17, .67, .83, .67)")
+//    if err != nil {
+//        return err
+//    }
+//    tween := NewTween(FPS(60), 0, 100, 0.3, ease)
+//
+// Supported are the keywords linear, ease, ease-in, ease-out, ease-in-out,
+// step-start and step-end, and the functions cubic-bezier(), steps() and
+// linear().
+//
+// For background on CSS timing functions see:
+// https://developer.mozilla.org/en-US/docs/Web/CSS/easing-function
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTimingFunction is returned when parsing a malformed or unsupported
+// CSS timing function.
+var ErrInvalidTimingFunction = errors.New("harmonica: invalid timing function")
+
+// numberPattern matches a CSS <number>. Unlike strconv.ParseFloat, it doesn't
+// accept NaN, infinities or hexadecimal.
+var numberPattern = regexp.MustCompile(`^[+-]?([0-9]*\.)?[0-9]+([eE][+-]?[0-9]+)?$`)
+
+// StepPosition is where the jumps happen in a step easing.
+type StepPosition int
+
+// Available step positions. They match the CSS keywords of the same names.
+const (
+	// JumpEnd holds each step until its end, so the last jump happens at the
+	// end of the animation. This is the default in CSS.
+	JumpEnd StepPosition = iota
+
+	// JumpStart jumps at the start of each step, so the first jump happens
+	// at the start of the animation.
+	JumpStart
+
+	// JumpNone jumps neither at the start nor the end, holding the first and
+	// last values for a full step each.
+	JumpNone
+
+	// JumpBoth jumps at both the start and the end.
+	JumpBoth
+)
+
+// Timing functions equivalent to the CSS keywords.
+var (
+	Ease      = CubicBezier(0.25, 0.1, 0.25, 1)
+	EaseIn    = CubicBezier(0.42, 0, 1, 1)
+	EaseOut   = CubicBezier(0, 0, 0.58, 1)
+	EaseInOut = CubicBezier(0.42, 0, 0.58, 1)
+	StepStart = Steps(1, JumpStart)
+	StepEnd   = Steps(1, JumpEnd)
+)
+
+// ParseTimingFunction parses a CSS timing function and returns the easing
+// function it describes.
+func ParseTimingFunction(s string) (EasingFunc, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	switch s {
+	case "linear":
+		return Linear, nil
+	case "ease":
+		return Ease, nil
+	case "ease-in":
+		return EaseIn, nil
+	case "ease-out":
+		return EaseOut, nil
+	case "ease-in-out":
+		return EaseInOut, nil
+	case "step-start":
+		return StepStart, nil
+	case "step-end":
+		return StepEnd, nil
+	}
+
+	open := strings.IndexByte(s, '(')
+	if open < 0 || !strings.HasSuffix(s, ")") {
+		return nil, invalidTimingFunction(s, "unknown keyword")
+	}
+
+	var (
+		name = strings.TrimSpace(s[:open])
+		args = strings.Split(s[open+1:len(s)-1], ",")
+	)
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+
+	var (
+		f   EasingFunc
+		err error
+	)
+	switch name {
+	case "cubic-bezier":
+		f, err = parseCubicBezier(args)
+	case "steps":
+		f, err = parseSteps(args)
+	case "linear":
+		f, err = parseLinear(args)
+	default:
+		err = fmt.Errorf("unknown function %q", name)
+	}
+	if err != nil {
+		return nil, invalidTimingFunction(s, err.Error())
+	}
+	return f, nil
+}
+
+// invalidTimingFunction returns an error wrapping ErrInvalidTimingFunction.
+func invalidTimingFunction(s, reason string) error {
+	return fmt.Errorf("%w %q: %s", ErrInvalidTimingFunction, s, reason)
+}
+
+// parseNumber parses a CSS <number>, which must also fit in a float64.
+func parseNumber(s string) (float64, error) {
+	if !numberPattern.MatchString(s) {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseCubicBezier(args []string) (EasingFunc, error) {
+	if len(args) != 4 {
+		return nil, fmt.Errorf("cubic-bezier takes 4 arguments, got %d", len(args))
+	}
+
+	var p [4]float64
+	for i, arg := range args {
+		v, err := parseNumber(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", arg)
+		}
+		p[i] = v
+	}
+	if p[0] < 0 || p[0] > 1 || p[2] < 0 || p[2] > 1 {
+		return nil, errors.New("cubic-bezier x values must be between 0 and 1")
+	}
+	return CubicBezier(p[0], p[1], p[2], p[3]), nil
+}
+
+func parseSteps(args []string) (EasingFunc, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("steps takes 1 or 2 arguments, got %d", len(args))
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid number of steps %q", args[0])
+	}
+
+	pos := JumpEnd
+	if len(args) == 2 {
+		switch args[1] {
+		case "jump-end", "end":
+			pos = JumpEnd
+		case "jump-start", "start":
+			pos = JumpStart
+		case "jump-none":
+			pos = JumpNone
+		case "jump-both":
+			pos = JumpBoth
+		default:
+			return nil, fmt.Errorf("invalid step position %q", args[1])
+		}
+	}
+	if pos == JumpNone && n < 2 {
+		return nil, errors.New("jump-none needs at least 2 steps")
+	}
+	return Steps(n, pos), nil
+}
+
+func parseLinear(args []string) (EasingFunc, error) {
+	var stops []linearStop
+	for _, arg := range args {
+		fields := strings.Fields(arg)
+		if len(fields) < 1 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid stop %q", arg)
+		}
+
+		out, err := parseNumber(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", fields[0])
+		}
+		if len(fields) == 1 {
+			stops = append(stops, linearStop{output: out, input: math.NaN()})
+			continue
+		}
+
+		// A stop with two percentages is shorthand for two stops.
+		for _, f := range fields[1:] {
+			if !strings.HasSuffix(f, "%") {
+				return nil, fmt.Errorf("invalid percentage %q", f)
+			}
+			in, err := parseNumber(strings.TrimSuffix(f, "%"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid percentage %q", f)
+			}
+			stops = append(stops, linearStop{output: out, input: in / 100})
+		}
+	}
+	if len(stops) < 2 {
+		return nil, errors.New("linear needs at least 2 stops")
+	}
+	return piecewiseLinear(stops), nil
+}
+
+// CubicBezier returns an easing function following a cubic Bézier curve from
+// (0, 0) to (1, 1) with the control points (x1, y1) and (x2, y2), like the
+// CSS cubic-bezier() function. The x values should be between 0 and 1; they're
+// clamped if they aren't. The y values may be outside that range, which makes
+// the easing overshoot.
+func CubicBezier(x1, y1, x2, y2 float64) EasingFunc {
+	x1 = clampUnit(x1)
+	x2 = clampUnit(x2)
+
+	// Polynomial coefficients of each coordinate: c·t + b·t² + a·t³.
+	var (
+		cx = 3 * x1
+		bx = 3*(x2-x1) - cx
+		ax = 1 - cx - bx
+		cy = 3 * y1
+		by = 3*(y2-y1) - cy
+		ay = 1 - cy - by
+	)
+
+	sampleX := func(t float64) float64 { return ((ax*t+bx)*t + cx) * t }
+	sampleY := func(t float64) float64 { return ((ay*t+by)*t + cy) * t }
+	slopeX := func(t float64) float64 { return (3*ax*t+2*bx)*t + cx }
+
+	const tolerance = 1e-7
+
+	// solve finds the curve parameter t for which the curve's x is x.
+	solve := func(x float64) float64 {
+		// Newton's method converges quickly for most curves.
+		t := x
+		for i := 0; i < 8; i++ {
+			err := sampleX(t) - x
+			if math.Abs(err) < tolerance && t >= 0 && t <= 1 {
+				return t
+			}
+			d := slopeX(t)
+			if math.Abs(d) < 1e-6 {
+				break
+			}
+			t -= err / d
+		}
+
+		// Fall back to bisection where the curve is too flat for Newton's
+		// method. x is monotonic in t because the x values are within [0, 1].
+		lo, hi := 0.0, 1.0
+		t = x
+		for i := 0; i < 64; i++ {
+			err := sampleX(t) - x
+			if math.Abs(err) < tolerance {
+				break
+			}
+			if err > 0 {
+				hi = t
+			} else {
+				lo = t
+			}
+			t = (lo + hi) / 2
+		}
+		return t
+	}
+
+	return func(x float64) float64 {
+		switch {
+		case x <= 0:
+			return 0
+		case x >= 1:
+			return 1
+		}
+		return sampleY(solve(x))
+	}
+}
+
+// Steps returns an easing function that jumps between n equal steps instead
+// of moving smoothly, like the CSS steps() function.
+func Steps(n int, position StepPosition) EasingFunc {
+	if n < 1 {
+		n = 1
+	}
+
+	jumps := n
+	switch position {
+	case JumpNone:
+		if n > 1 {
+			jumps = n - 1
+		}
+	case JumpBoth:
+		jumps = n + 1
+	}
+
+	return func(x float64) float64 {
+		step := math.Floor(x * float64(n))
+		if position == JumpStart || position == JumpBoth {
+			step++
+		}
+		if x >= 0 && step < 0 {
+			step = 0
+		}
+		if x <= 1 && step > float64(jumps) {
+			step = float64(jumps)
+		}
+		return step / float64(jumps)
+	}
+}
+
+// linearStop is a point in a CSS linear() easing. A NaN input means it
+// wasn't given and is spaced evenly between its neighbors.
+type linearStop struct {
+	output float64
+	input  float64
+}
+
+// piecewiseLinear returns an easing function interpolating linearly between
+// the given stops, following the rules of the CSS linear() function.
+func piecewiseLinear(stops []linearStop) EasingFunc {
+	// The first and last stops default to the start and end.
+	if math.IsNaN(stops[0].input) {
+		stops[0].input = 0
+	}
+	if last := len(stops) - 1; math.IsNaN(stops[last].input) {
+		stops[last].input = 1
+	}
+
+	// Inputs never go backwards.
+	largest := stops[0].input
+	for i := range stops {
+		if math.IsNaN(stops[i].input) {
+			continue
+		}
+		largest = math.Max(largest, stops[i].input)
+		stops[i].input = largest
+	}
+
+	// Space out runs of stops without inputs evenly.
+	for i := 1; i < len(stops); i++ {
+		if !math.IsNaN(stops[i].input) {
+			continue
+		}
+		j := i
+		for math.IsNaN(stops[j].input) {
+			j++
+		}
+		var (
+			from = stops[i-1].input
+			to   = stops[j].input
+			n    = float64(j - i + 1)
+		)
+		for k := i; k < j; k++ {
+			stops[k].input = from + (to-from)*float64(k-i+1)/n
+		}
+		i = j
+	}
+
+	return func(x float64) float64 {
+		// Find the last stop at or before x, extending the first and last
+		// segments past the ends.
+		i := 0
+		for i < len(stops)-2 && stops[i+1].input <= x {
+			i++
+		}
+		a, b := stops[i], stops[i+1]
+		if a.input == b.input {
+			return b.output
+		}
+		return lerp(a.output, b.output, (x-a.input)/(b.input-a.input))
+	}
+}
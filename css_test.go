@@ -0,0 +1,97 @@
+package harmonica_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestParseTimingFunction(t *testing.T) {
+	for _, tc := range []struct {
+		css  string
+		in   []float64
+		want []float64
+	}{
+		{"linear", []float64{0, 0.3, 1}, []float64{0, 0.3, 1}},
+		{"ease", []float64{0, 0.5, 1}, []float64{0, 0.8024, 1}},
+		{"ease-in-out", []float64{0.5}, []float64{0.5}},
+		{"cubic-bezier(.17,.67,.83,.67)", []float64{0, 0.5, 1}, []float64{0, 0.6275, 1}},
+		{"cubic-bezier(0.68, -0.6, 0.32, 1.6)", []float64{0.1, 0.9}, []float64{-0.0728, 1.0728}},
+		{"steps(4, jump-end)", []float64{0, 0.24, 0.25, 0.99, 1}, []float64{0, 0, 0.25, 0.75, 1}},
+		{"steps(4)", []float64{0.5}, []float64{0.5}},
+		{"steps(4, jump-start)", []float64{0, 0.3, 1}, []float64{0.25, 0.5, 1}},
+		{"steps(3, jump-none)", []float64{0, 0.4, 0.9}, []float64{0, 0.5, 1}},
+		{"steps(3, jump-both)", []float64{0, 0.5, 1}, []float64{0.25, 0.5, 1}},
+		{"step-start", []float64{0, 0.5}, []float64{1, 1}},
+		{"step-end", []float64{0.5, 1}, []float64{0, 1}},
+		{"linear(0, 0.25 75%, 1)", []float64{0, 0.375, 0.75, 0.875, 1}, []float64{0, 0.125, 0.25, 0.625, 1}},
+		{"linear(0, 0.25, 1)", []float64{0.25, 0.75}, []float64{0.125, 0.625}},
+		{"linear(0, 0.5 25% 75%, 1)", []float64{0.125, 0.5, 0.875}, []float64{0.25, 0.5, 0.75}},
+		{"linear(0, 1 50%, 0 50%, 1)", []float64{0.25, 0.5, 0.75}, []float64{0.5, 0, 0.5}},
+		{"  LINEAR(0 , 1)  ", []float64{0.5}, []float64{0.5}},
+	} {
+		f, err := ParseTimingFunction(tc.css)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.css, err)
+		}
+		for i, x := range tc.in {
+			if got := f(x); math.Abs(got-tc.want[i]) > 1e-3 {
+				t.Logf("Want: %.4f", tc.want[i])
+				t.Logf("Got:  %.4f", got)
+				t.Fatalf("%s at %.3f unexpected", tc.css, x)
+			}
+		}
+	}
+}
+
+func TestParseTimingFunctionErrors(t *testing.T) {
+	for _, css := range []string{
+		"",
+		"bounce",
+		"cubic-bezier(1, 2, 3)",
+		"cubic-bezier(1.5, 0, 0.5, 1)",
+		"cubic-bezier(a, 0, 0.5, 1)",
+		"steps(0)",
+		"steps(2.5)",
+		"steps(1, jump-none)",
+		"steps(2, sideways)",
+		"linear(1)",
+		"linear(0, 1 50)",
+		"spring(1, 2)",
+		"linear(0, 1",
+		"cubic-bezier(nan, 0, 0.5, inf)",
+		"cubic-bezier(0.5, 0, 0.5, infinity)",
+		"cubic-bezier(0x1p-1, 0, 0.5, 1)",
+		"cubic-bezier(0.5, 1e400, 0.5, 1)",
+		"linear(0, nan, 1)",
+		"linear(0, 1 nan%)",
+		"linear(0, 1.)",
+	} {
+		if _, err := ParseTimingFunction(css); !errors.Is(err, ErrInvalidTimingFunction) {
+			t.Fatalf("%q: expected ErrInvalidTimingFunction, got %v", css, err)
+		}
+	}
+}
+
+func TestCubicBezierMonotonic(t *testing.T) {
+	// Nearly vertical and horizontal sections are hard on Newton's method.
+	for _, f := range []EasingFunc{
+		CubicBezier(0, 1, 0, 1),
+		CubicBezier(1, 0, 1, 0),
+		CubicBezier(0.9, 0, 0.1, 1),
+	} {
+		prev := f(0)
+		for i := 1; i <= 1000; i++ {
+			v := f(float64(i) / 1000)
+			if v < prev-1e-6 {
+				t.Fatalf("cubic-bezier went backwards at %.3f: %.6f < %.6f", float64(i)/1000, v, prev)
+			}
+			prev = v
+		}
+		if prev != 1 {
+			t.Fatalf("expected cubic-bezier to end at 1, got %.4f", prev)
+		}
+	}
+}
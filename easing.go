@@ -0,0 +1,281 @@
+package harmonica
+
+// This file defines easing functions, which shape the progress of an
+// animation over a fixed duration. They're Robert Penner's classic easings,
+// each in three flavors: In starts slowly, Out ends slowly, and InOut does
+// both.
+//
+// Example usage:
+//
+//    // Half way through, an ease-out animation is already well past
+//    // half way.
+//    progress := EaseOutCubic(0.5) // 0.875
+//
+// For background on easing functions see:
+// https://easings.net/
+
+import (
+	"math"
+	"time"
+)
+
+// EasingFunc maps linear progress, from 0 to 1, to eased progress. Eased
+// progress starts at 0 and ends at 1, but may overshoot in between, as Back
+// and Elastic easings do.
+type EasingFunc func(t float64) float64
+
+// Constants used by the Back and Elastic easings.
+const (
+	backOvershoot   = 1.70158
+	backOvershootIO = backOvershoot * 1.525
+	elasticPeriod   = 2 * math.Pi / 3
+	elasticPeriodIO = 2 * math.Pi / 4.5
+)
+
+// Linear doesn't ease at all.
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInSine eases in along a quarter sine wave.
+func EaseInSine(t float64) float64 {
+	return 1 - math.Cos(t*math.Pi/2)
+}
+
+// EaseOutSine eases out along a quarter sine wave.
+func EaseOutSine(t float64) float64 {
+	return math.Sin(t * math.Pi / 2)
+}
+
+// EaseInOutSine eases in and out along half a sine wave.
+func EaseInOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
+
+// EaseInQuad eases in with t².
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad eases out with t².
+func EaseOutQuad(t float64) float64 {
+	return easeOut(EaseInQuad, t)
+}
+
+// EaseInOutQuad eases in and out with t².
+func EaseInOutQuad(t float64) float64 {
+	return easeInOut(EaseInQuad, t)
+}
+
+// EaseInCubic eases in with t³.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic eases out with t³.
+func EaseOutCubic(t float64) float64 {
+	return easeOut(EaseInCubic, t)
+}
+
+// EaseInOutCubic eases in and out with t³.
+func EaseInOutCubic(t float64) float64 {
+	return easeInOut(EaseInCubic, t)
+}
+
+// EaseInQuart eases in with t⁴.
+func EaseInQuart(t float64) float64 {
+	return t * t * t * t
+}
+
+// EaseOutQuart eases out with t⁴.
+func EaseOutQuart(t float64) float64 {
+	return easeOut(EaseInQuart, t)
+}
+
+// EaseInOutQuart eases in and out with t⁴.
+func EaseInOutQuart(t float64) float64 {
+	return easeInOut(EaseInQuart, t)
+}
+
+// EaseInQuint eases in with t⁵.
+func EaseInQuint(t float64) float64 {
+	return t * t * t * t * t
+}
+
+// EaseOutQuint eases out with t⁵.
+func EaseOutQuint(t float64) float64 {
+	return easeOut(EaseInQuint, t)
+}
+
+// EaseInOutQuint eases in and out with t⁵.
+func EaseInOutQuint(t float64) float64 {
+	return easeInOut(EaseInQuint, t)
+}
+
+// EaseInExpo eases in exponentially.
+func EaseInExpo(t float64) float64 {
+	if t <= 0 {
+		return 0
+	}
+	return math.Pow(2, 10*t-10)
+}
+
+// EaseOutExpo eases out exponentially.
+func EaseOutExpo(t float64) float64 {
+	return easeOut(EaseInExpo, t)
+}
+
+// EaseInOutExpo eases in and out exponentially.
+func EaseInOutExpo(t float64) float64 {
+	return easeInOut(EaseInExpo, t)
+}
+
+// EaseInCirc eases in along a quarter circle.
+func EaseInCirc(t float64) float64 {
+	return 1 - math.Sqrt(math.Max(0, 1-t*t))
+}
+
+// EaseOutCirc eases out along a quarter circle.
+func EaseOutCirc(t float64) float64 {
+	return easeOut(EaseInCirc, t)
+}
+
+// EaseInOutCirc eases in and out along two quarter circles.
+func EaseInOutCirc(t float64) float64 {
+	return easeInOut(EaseInCirc, t)
+}
+
+// EaseInBack pulls back slightly before moving forward.
+func EaseInBack(t float64) float64 {
+	return (backOvershoot+1)*t*t*t - backOvershoot*t*t
+}
+
+// EaseOutBack overshoots slightly before settling.
+func EaseOutBack(t float64) float64 {
+	return easeOut(EaseInBack, t)
+}
+
+// EaseInOutBack pulls back at the start and overshoots at the end.
+func EaseInOutBack(t float64) float64 {
+	return easeInOut(func(t float64) float64 {
+		return (backOvershootIO+1)*t*t*t - backOvershootIO*t*t
+	}, t)
+}
+
+// EaseInElastic winds up like a stretched rubber band before letting go.
+func EaseInElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return clampUnit(t)
+	}
+	return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*elasticPeriod)
+}
+
+// EaseOutElastic wobbles around the end like a plucked rubber band.
+func EaseOutElastic(t float64) float64 {
+	return easeOut(EaseInElastic, t)
+}
+
+// EaseInOutElastic winds up at the start and wobbles at the end.
+func EaseInOutElastic(t float64) float64 {
+	return easeInOut(func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return clampUnit(t)
+		}
+		return -math.Pow(2, 10*t-10) * math.Sin((t*10-11.125)*elasticPeriodIO)
+	}, t)
+}
+
+// EaseInBounce bounces with growing height before taking off.
+func EaseInBounce(t float64) float64 {
+	return 1 - EaseOutBounce(1-t)
+}
+
+// EaseOutBounce falls and bounces to a stop at the end, like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	const (
+		n = 7.5625
+		d = 2.75
+	)
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// EaseInOutBounce bounces in at the start and out at the end.
+func EaseInOutBounce(t float64) float64 {
+	return easeInOut(EaseInBounce, t)
+}
+
+// Easing returns an easing function that follows the spring's motion from 0
+// to 1, normalized to the time it takes to settle, along with that time. It
+// lets a spring drive anything that accepts an easing function, such as a
+// Tween, which should then run for the returned duration.
+//
+// The tolerance determines when the spring is considered settled, as
+// a fraction of the distance moved; 0.001 is a good place to start. The motion
+// is precomputed into a table, so evaluating the easing function is cheap.
+func (s Spring) Easing(tolerance float64) (EasingFunc, time.Duration) {
+	var (
+		pos, vel = s.response(tolerance)
+		n        = len(pos) - 1
+		dt       = s.deltaTime
+	)
+
+	return func(t float64) float64 {
+		switch {
+		case t <= 0:
+			return 0
+		case t >= 1:
+			return 1
+		}
+
+		x := t * float64(n)
+		i := int(x)
+		if i >= n {
+			i = n - 1
+		}
+
+		// Interpolate with a cubic Hermite spline, using the velocity at each
+		// sample as its slope, which is far more accurate than a straight
+		// line between samples.
+		var (
+			u  = x - float64(i)
+			u2 = u * u
+			u3 = u2 * u
+		)
+		return (2*u3-3*u2+1)*pos[i] +
+			(u3-2*u2+u)*vel[i]*dt +
+			(-2*u3+3*u2)*pos[i+1] +
+			(u3-u2)*vel[i+1]*dt
+	}, s.responseDuration(len(pos))
+}
+
+// easeOut returns the ease-out counterpart of an ease-in function by playing
+// it backwards.
+func easeOut(in EasingFunc, t float64) float64 {
+	return 1 - in(1-t)
+}
+
+// easeInOut returns the ease-in-out counterpart of an ease-in function: the
+// first half eases in and the second half eases out.
+func easeInOut(in EasingFunc, t float64) float64 {
+	if t < 0.5 {
+		return in(t*2) / 2
+	}
+	return 1 - in(2-t*2)/2
+}
+
+// clampUnit clamps t to [0, 1].
+func clampUnit(t float64) float64 {
+	return math.Max(0, math.Min(1, t))
+}
@@ -0,0 +1,156 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+var easings = map[string]EasingFunc{
+	"Linear":           Linear,
+	"EaseInSine":       EaseInSine,
+	"EaseOutSine":      EaseOutSine,
+	"EaseInOutSine":    EaseInOutSine,
+	"EaseInQuad":       EaseInQuad,
+	"EaseOutQuad":      EaseOutQuad,
+	"EaseInOutQuad":    EaseInOutQuad,
+	"EaseInCubic":      EaseInCubic,
+	"EaseOutCubic":     EaseOutCubic,
+	"EaseInOutCubic":   EaseInOutCubic,
+	"EaseInQuart":      EaseInQuart,
+	"EaseOutQuart":     EaseOutQuart,
+	"EaseInOutQuart":   EaseInOutQuart,
+	"EaseInQuint":      EaseInQuint,
+	"EaseOutQuint":     EaseOutQuint,
+	"EaseInOutQuint":   EaseInOutQuint,
+	"EaseInExpo":       EaseInExpo,
+	"EaseOutExpo":      EaseOutExpo,
+	"EaseInOutExpo":    EaseInOutExpo,
+	"EaseInCirc":       EaseInCirc,
+	"EaseOutCirc":      EaseOutCirc,
+	"EaseInOutCirc":    EaseInOutCirc,
+	"EaseInBack":       EaseInBack,
+	"EaseOutBack":      EaseOutBack,
+	"EaseInOutBack":    EaseInOutBack,
+	"EaseInElastic":    EaseInElastic,
+	"EaseOutElastic":   EaseOutElastic,
+	"EaseInOutElastic": EaseInOutElastic,
+	"EaseInBounce":     EaseInBounce,
+	"EaseOutBounce":    EaseOutBounce,
+	"EaseInOutBounce":  EaseInOutBounce,
+}
+
+func TestEasingEndpoints(t *testing.T) {
+	for name, ease := range easings {
+		if v := ease(0); !equal(v, 0) {
+			t.Fatalf("%s(0) = %.4f, want 0", name, v)
+		}
+		if v := ease(1); !equal(v, 1) {
+			t.Fatalf("%s(1) = %.4f, want 1", name, v)
+		}
+	}
+}
+
+func TestEasingContinuous(t *testing.T) {
+	// Sample densely enough that even the steepest easings, such as the
+	// circular ones at their ends, move less than the bound between samples,
+	// so any larger step is a jump.
+	const (
+		samples = 10000
+		maxStep = 0.02
+	)
+	for name, ease := range easings {
+		prev := ease(0)
+		for i := 1; i <= samples; i++ {
+			x := float64(i) / samples
+			v := ease(x)
+			if d := math.Abs(v - prev); d > maxStep {
+				t.Logf("Want: step of at most %.4f", maxStep)
+				t.Logf("Got:  %.4f", d)
+				t.Fatalf("%s jumps at t=%.4f", name, x)
+			}
+			prev = v
+		}
+	}
+}
+
+func TestEasingValues(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		t    float64
+		want float64
+	}{
+		{"EaseInQuad", 0.5, 0.25},
+		{"EaseOutCubic", 0.5, 0.875},
+		{"EaseInOutQuad", 0.25, 0.125},
+		{"EaseInOutCubic", 0.5, 0.5},
+		{"EaseInBack", 0.5, -0.0877},
+		{"EaseOutBounce", 0.5, 0.7656},
+		{"EaseInExpo", 0.5, 0.03125},
+	} {
+		if got := easings[tc.name](tc.t); !equal(got, tc.want) {
+			t.Logf("Want: %.4f", tc.want)
+			t.Logf("Got:  %.4f", got)
+			t.Fatalf("%s(%.2f) unexpected", tc.name, tc.t)
+		}
+	}
+}
+
+func TestEasingOvershoot(t *testing.T) {
+	var peak float64
+	for i := 0; i <= 100; i++ {
+		if v := EaseOutBack(float64(i) / 100); v > peak {
+			peak = v
+		}
+	}
+	if peak <= 1.05 {
+		t.Fatalf("expected EaseOutBack to overshoot, peaked at %.3f", peak)
+	}
+}
+
+func TestSpringEasing(t *testing.T) {
+	const tolerance = 0.001
+
+	for _, damping := range []float64{0.2, 1, 2} {
+		s := NewSpring(FPS(fps), 6, damping)
+		ease, duration := s.Easing(tolerance)
+		if duration != s.Duration(tolerance) {
+			t.Fatalf("unexpected duration %s", duration)
+		}
+
+		// Compare against a spring updated four times as often, so half the
+		// checks fall between the table's samples.
+		var (
+			fine     = NewSpring(FPS(fps*4), 6, damping)
+			steps    = int(math.Round(duration.Seconds() * fps * 4))
+			pos, vel float64
+		)
+		for i := 1; i < steps; i++ {
+			pos, vel = fine.Update(pos, vel, 1)
+			if got := ease(float64(i) / float64(steps)); math.Abs(got-pos) > tolerance*2 {
+				t.Logf("Want: %.4f", pos)
+				t.Logf("Got:  %.4f", got)
+				t.Fatalf("easing with damping %.1f diverged at step %d of %d", damping, i, steps)
+			}
+		}
+		if ease(0) != 0 || ease(1) != 1 {
+			t.Fatalf("expected easing from 0 to 1, got %.4f to %.4f", ease(0), ease(1))
+		}
+	}
+}
+
+func TestSpringEasingTween(t *testing.T) {
+	ease, duration := NewSpring(FPS(fps), 8, 0.5).Easing(0.001)
+	tw := NewTween(FPS(fps), 0, 100, duration.Seconds(), ease)
+
+	var overshot bool
+	for !tw.Done() {
+		if tw.Update() > 100 {
+			overshot = true
+		}
+	}
+	if !overshot || tw.Value() != 100 {
+		t.Fatalf("expected tween to overshoot and settle at 100, got %.2f", tw.Value())
+	}
+}
@@ -0,0 +1,183 @@
+package harmonica
+
+// This file defines environments: the gravity, air and wind that projectiles
+// move through, along with the scale of the world they're drawn in.
+//
+// Physical quantities in an environment are in SI units: meters, seconds and
+// kilograms. Projectiles, however, live in whatever units you draw them in,
+// such as terminal cells or pixels. The environment's scale converts between
+// the two, so a projectile falling on the Moon falls at the right rate
+// whether a meter is one cell or eight pixels.
+//
+// Example usage:
+//
+//    // Run once to initialize. Here a meter is two terminal cells and the Y
+//    // axis points down.
+//    env := Earth.WithScale(2).WithDown(Vector{0, 1, 0})
+//    projectile := env.NewProjectile(FPS(60), Point{0, 0, 0}, Vector{10, -20, 0}, 0.01)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        pos := projectile.Update()
+//    })
+
+import "math"
+
+// Environment describes the world projectiles move through.
+type Environment struct {
+	// Down is the direction gravity pulls in. It needn't be normalized. A zero
+	// vector means down is along the negative Y axis, as with Gravity.
+	Down Vector
+
+	// Gravity is the magnitude of gravitational acceleration in m/s².
+	Gravity float64
+
+	// AirDensity is the density of the surrounding medium in kg/m³. It
+	// determines how much drag projectiles experience.
+	AirDensity float64
+
+	// Wind is the velocity of the surrounding medium in m/s.
+	Wind Vector
+
+	// Scale is the number of units, such as cells or pixels, per meter.
+	// Zero is treated as 1.
+	Scale float64
+}
+
+// Environment presets. They all have a scale of one unit per meter with the
+// Y axis pointing up; use WithScale and WithDown to adapt them to your
+// coordinate system.
+var (
+	// Earth at sea level.
+	Earth = Environment{Gravity: 9.81, AirDensity: 1.225}
+
+	// Moon has weak gravity and no atmosphere.
+	Moon = Environment{Gravity: 1.62}
+
+	// Mars has about a third of Earth's gravity and a thin atmosphere.
+	Mars = Environment{Gravity: 3.72, AirDensity: 0.020}
+
+	// ZeroG is empty space, far from anything.
+	ZeroG = Environment{}
+
+	// Underwater is fresh water on Earth. Buoyancy isn't modeled, so
+	// projectiles sink at Earth's gravity, slowed by the water's drag.
+	Underwater = Environment{Gravity: 9.81, AirDensity: 1000}
+)
+
+// WithScale returns a copy of the environment with the given number of units
+// per meter.
+func (e Environment) WithScale(unitsPerMeter float64) Environment {
+	e.Scale = unitsPerMeter
+	return e
+}
+
+// WithDown returns a copy of the environment with gravity pulling in the given
+// direction. Use Vector{0, 1, 0} for terminals, where the Y axis points down.
+func (e Environment) WithDown(down Vector) Environment {
+	e.Down = down
+	return e
+}
+
+// WithWind returns a copy of the environment with the given wind velocity in
+// m/s.
+func (e Environment) WithWind(wind Vector) Environment {
+	e.Wind = wind
+	return e
+}
+
+// scale returns the number of units per meter.
+func (e Environment) scale() float64 {
+	if e.Scale <= 0 {
+		return 1
+	}
+	return e.Scale
+}
+
+// ToUnits converts a distance in meters to units.
+func (e Environment) ToUnits(meters float64) float64 {
+	return meters * e.scale()
+}
+
+// ToMeters converts a distance in units to meters.
+func (e Environment) ToMeters(units float64) float64 {
+	return units / e.scale()
+}
+
+// Acceleration returns the acceleration due to gravity in units/s². It can be
+// used wherever Gravity or TerminalGravity would be.
+func (e Environment) Acceleration() Vector {
+	down := e.Down.Normalize()
+	if down == (Vector{}) {
+		down = Vector{0, -1, 0}
+	}
+	return down.Scale(e.Gravity * e.scale())
+}
+
+// Drag returns a force that slows projectiles moving through the environment's
+// air and pushes them along with its wind.
+//
+// The drag coefficient is CdA/2m in m²/kg, where Cd is the projectile's drag
+// coefficient, A its cross-sectional area and m its mass. Larger values are
+// slowed more; a ping-pong ball is about 0.1 and a baseball about 0.004.
+func (e Environment) Drag(dragCoefficient float64) Force {
+	return airDrag{
+		k:    e.AirDensity * dragCoefficient / e.scale(),
+		wind: e.Wind.Scale(e.scale()),
+	}
+}
+
+// NewProjectile creates a new projectile moving through the environment, with
+// position and velocity in units. See Drag for the drag coefficient; zero
+// ignores the air entirely.
+func (e Environment) NewProjectile(deltaTime float64, initialPosition Point, initialVelocity Vector, dragCoefficient float64) *Projectile {
+	p := NewProjectile(deltaTime, initialPosition, initialVelocity, e.Acceleration())
+	if dragCoefficient > 0 && e.AirDensity > 0 {
+		p.AddForce(e.Drag(dragCoefficient))
+	}
+	return p
+}
+
+// NewParticleSystem creates a new particle system in the environment. Every
+// particle is pulled by the environment's gravity in addition to its emitter's
+// acceleration, and slowed by the air according to the drag coefficient. See
+// Drag for details.
+func (e Environment) NewParticleSystem(capacity int, seed int64, dragCoefficient float64) *ParticleSystem {
+	s := NewParticleSystem(capacity, seed)
+	s.AddForce(constantForce(e.Acceleration()))
+	if dragCoefficient > 0 && e.AirDensity > 0 {
+		s.AddForce(e.Drag(dragCoefficient))
+	}
+	return s
+}
+
+// TerminalVelocity returns the speed, in units/s, at which drag balances
+// gravity for a projectile with the given drag coefficient. It's infinite
+// without air or drag.
+func (e Environment) TerminalVelocity(dragCoefficient float64) float64 {
+	k := e.AirDensity * dragCoefficient
+	if k <= 0 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(e.Gravity/k) * e.scale()
+}
+
+// airDrag is quadratic drag, relative to the wind, in scaled units.
+type airDrag struct {
+	k    float64
+	wind Vector
+}
+
+// Acceleration implements Force.
+func (d airDrag) Acceleration(_ Point, vel Vector, _ float64) Vector {
+	rel := vel.Sub(d.wind)
+	return rel.Scale(-d.k * rel.Length())
+}
+
+// constantForce is a uniform acceleration, like gravity.
+type constantForce Vector
+
+// Acceleration implements Force.
+func (c constantForce) Acceleration(Point, Vector, float64) Vector {
+	return Vector(c)
+}
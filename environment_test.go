@@ -0,0 +1,86 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestEnvironmentAcceleration(t *testing.T) {
+	if got := Earth.Acceleration(); !equalVector(got, Gravity) {
+		t.Fatalf("expected Earth to match Gravity, got %+v", got)
+	}
+
+	terminal := Earth.WithDown(Vector{0, 1, 0})
+	if got := terminal.Acceleration(); !equalVector(got, TerminalGravity) {
+		t.Fatalf("expected terminal Earth to match TerminalGravity, got %+v", got)
+	}
+
+	// Eight pixels per meter on the Moon.
+	moon := Moon.WithScale(8)
+	if got := moon.Acceleration(); !equalVector(got, Vector{0, -1.62 * 8, 0}) {
+		t.Fatalf("scaled Moon gravity unexpected: %+v", got)
+	}
+	if !equal(moon.ToUnits(2), 16) || !equal(moon.ToMeters(16), 2) {
+		t.Fatal("unit conversion unexpected")
+	}
+
+	if got := ZeroG.Acceleration(); got != (Vector{}) {
+		t.Fatalf("expected no gravity in ZeroG, got %+v", got)
+	}
+}
+
+func TestEnvironmentTerminalVelocity(t *testing.T) {
+	const drag = 0.1
+	env := Earth.WithScale(4)
+	p := env.NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{}, drag)
+
+	for i := 0; i < fps*10; i++ {
+		p.Update()
+	}
+
+	want := env.TerminalVelocity(drag)
+	if got := -p.Velocity().Y; math.Abs(got-want) > 0.1 {
+		t.Logf("Want: %.2f", want)
+		t.Logf("Got:  %.2f", got)
+		t.Fatal("terminal velocity unexpected")
+	}
+
+	if v := Moon.TerminalVelocity(drag); !math.IsInf(v, 1) {
+		t.Fatalf("expected no terminal velocity without air, got %.2f", v)
+	}
+}
+
+func TestEnvironmentWind(t *testing.T) {
+	env := Underwater.WithDown(Vector{0, 0, -1}).WithWind(Vector{2, 0, 0}).WithScale(3)
+	p := env.NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{}, 0.01)
+
+	for i := 0; i < fps*5; i++ {
+		p.Update()
+	}
+
+	// Carried along by the current.
+	if got := p.Velocity().X; !equal(got, 6) {
+		t.Logf("Want: %.2f", 6.0)
+		t.Logf("Got:  %.2f", got)
+		t.Fatal("velocity unexpected")
+	}
+}
+
+func TestEnvironmentParticles(t *testing.T) {
+	s := Mars.NewParticleSystem(10, 1, 0)
+	s.AddEmitter(&Emitter{MinLifetime: 10, MaxLifetime: 10, Burst: 10})
+
+	for i := 0; i <= fps; i++ {
+		s.Update(FPS(fps))
+	}
+
+	for _, p := range s.Particles() {
+		if got := p.Velocity().Y; !equal(got, -3.72) {
+			t.Logf("Want: %.2f", -3.72)
+			t.Logf("Got:  %.2f", got)
+			t.Fatal("particle velocity unexpected")
+		}
+	}
+}
@@ -2,11 +2,15 @@ module examples
 
 go 1.16
 
-replace github.com/charmbracelet/harmonica => ../
+replace (
+	github.com/charmbracelet/harmonica => ../
+	github.com/charmbracelet/harmonica/animation => ../animation
+)
 
 require (
 	github.com/charmbracelet/bubbletea v0.14.1
 	github.com/charmbracelet/harmonica v0.0.0-20210709144143-16876a63b30d
+	github.com/charmbracelet/harmonica/animation v0.0.0-00010101000000-000000000000
 	github.com/charmbracelet/lipgloss v0.3.0
 	github.com/faiface/pixel v0.10.0
 	github.com/fogleman/gg v1.3.0
@@ -8,6 +8,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/harmonica"
+	"github.com/charmbracelet/harmonica/animation"
 )
 
 const (
@@ -15,14 +16,6 @@ const (
 	maxHeight = 100
 )
 
-type frameMsg time.Time
-
-func animate() tea.Cmd {
-	return tea.Tick(time.Second/fps, func(t time.Time) tea.Msg {
-		return frameMsg(t)
-	})
-}
-
 func wait(d time.Duration) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(d)
@@ -31,28 +24,30 @@ func wait(d time.Duration) tea.Cmd {
 }
 
 type model struct {
-	projectile *harmonica.Projectile
-	pos        harmonica.Point
+	anim       *animation.Model
+	projectile *animation.Projectile
+	start      tea.Cmd
 }
 
-func (_ model) Init() tea.Cmd {
-	return tea.Sequentially(wait(time.Second/2), animate())
+func (m model) Init() tea.Cmd {
+	return tea.Sequentially(wait(time.Second/2), m.start)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m, tea.Quit
 
 	// Step forward one frame
-	case frameMsg:
-		m.pos = m.projectile.Update()
+	case animation.FrameMsg:
+		cmd := m.anim.Update(msg)
 
-		if m.pos.Y > maxHeight {
+		// Quit once the projectile has fallen out of view.
+		if !m.anim.Running() {
 			return m, tea.Quit
 		}
 
-		return m, animate()
+		return m, cmd
 	default:
 		return m, nil
 	}
@@ -60,15 +55,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m model) View() string {
 	var out strings.Builder
+	pos := m.projectile.Position()
 
-	for y := 0; y < int(m.pos.Y); y++ {
+	for y := 0; y < int(pos.Y); y++ {
 		out.WriteString("\n")
 	}
 
-	for x := 0; x < int(m.pos.X); x++ {
+	for x := 0; x < int(pos.X); x++ {
 		out.WriteString(" ")
 	}
-	out.WriteString(fmt.Sprintf("(%.2f, %.2f)", m.pos.X, m.pos.Y))
+	out.WriteString(fmt.Sprintf("(%.2f, %.2f)", pos.X, pos.Y))
 
 	return out.String()
 }
@@ -77,9 +73,15 @@ func main() {
 	initPos := harmonica.Point{X: 0, Y: 0}
 	initVel := harmonica.Vector{X: 5, Y: 0}
 	initAcc := harmonica.TerminalGravity
-	m := model{
-		projectile: harmonica.NewProjectile(harmonica.FPS(fps), initPos, initVel, initAcc),
-	}
+	anim := animation.New(fps)
+
+	// The program isn't running yet, so hold on to the command that starts
+	// ticking until Init.
+	projectile, start := anim.AddProjectile(
+		harmonica.NewProjectile(harmonica.FPS(fps), initPos, initVel, initAcc),
+		func(p *harmonica.Projectile) bool { return p.Position().Y > maxHeight },
+	)
+	m := model{anim: anim, projectile: projectile, start: start}
 
 	if err := tea.NewProgram(m).Start(); err != nil {
 		fmt.Println("Error running program:", err)
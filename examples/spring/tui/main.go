@@ -8,7 +8,7 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/harmonica"
+	"github.com/charmbracelet/harmonica/animation"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -18,6 +18,7 @@ const (
 	spriteHeight = 5
 	frequency    = 7.0
 	damping      = 0.15
+	targetX      = 60
 )
 
 var (
@@ -31,14 +32,6 @@ var (
 			Background(lipgloss.Color("#575BD8"))
 )
 
-type frameMsg time.Time
-
-func animate() tea.Cmd {
-	return tea.Tick(time.Second/fps, func(t time.Time) tea.Msg {
-		return frameMsg(t)
-	})
-}
-
 func wait(d time.Duration) tea.Cmd {
 	return func() tea.Msg {
 		time.Sleep(d)
@@ -47,34 +40,29 @@ func wait(d time.Duration) tea.Cmd {
 }
 
 type model struct {
-	x      float64
-	xVel   float64
-	spring harmonica.Spring
+	anim *animation.Model
+	x    *animation.Spring
 }
 
-func (_ model) Init() tea.Cmd {
-	return tea.Sequentially(wait(time.Second/2), animate())
+func (m model) Init() tea.Cmd {
+	return tea.Sequentially(wait(time.Second/2), m.x.SetTarget(targetX))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m, tea.Quit
 
-	// Step foreward one frame
-	case frameMsg:
-		const targetX = 60
-
-		// Update x position (and velocity) with our spring.
-		m.x, m.xVel = m.spring.Update(m.x, m.xVel, targetX)
+	// Step forward one frame
+	case animation.FrameMsg:
+		cmd := m.anim.Update(msg)
 
-		// Quit when we're basically at the target position.
-		if math.Abs(m.x-targetX) < 0.01 {
+		// Quit once the spring has settled at the target position.
+		if !m.anim.Running() {
 			return m, tea.Sequentially(wait(3/4*time.Second), tea.Quit)
 		}
 
-		// Request next frame
-		return m, animate()
+		return m, cmd
 
 	default:
 		return m, nil
@@ -85,7 +73,7 @@ func (m model) View() string {
 	var out strings.Builder
 	fmt.Fprint(&out, "\n")
 
-	x := int(math.Round(m.x))
+	x := int(math.Round(m.x.Position()))
 	if x < 0 {
 		return ""
 	}
@@ -100,8 +88,10 @@ func (m model) View() string {
 }
 
 func main() {
+	anim := animation.New(fps)
 	m := model{
-		spring: harmonica.NewSpring(harmonica.FPS(fps), frequency, damping),
+		anim: anim,
+		x:    anim.AddSpring(frequency, damping, 0),
 	}
 
 	if err := tea.NewProgram(m).Start(); err != nil {
@@ -0,0 +1,192 @@
+package harmonica
+
+// This file defines force fields which can be layered on projectiles and
+// particle systems in addition to their constant acceleration.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    projectile := NewProjectile(
+//        FPS(60),
+//        Point{0, 0, 0},
+//        Vector{5, 0, 0},
+//        TerminalGravity,
+//    )
+//    projectile.AddForce(Wind{Velocity: Vector{-3, 0, 0}, Drag: 0.5})
+//    projectile.AddForce(Attractor{Position: Point{40, 10, 0}, Strength: 20})
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        pos := projectile.Update()
+//    })
+
+import "math"
+
+// Force is a field that accelerates projectiles. It's evaluated once per
+// projectile per step with the projectile's current position and velocity
+// and the time, in seconds, that the projectile has been simulated for.
+//
+// Forces return an acceleration rather than a force proper, so they affect
+// everything in the same way regardless of mass, like gravity does.
+type Force interface {
+	Acceleration(pos Point, vel Vector, t float64) Vector
+}
+
+// ForceFunc is an adapter to allow the use of ordinary functions as forces.
+type ForceFunc func(pos Point, vel Vector, t float64) Vector
+
+// Acceleration calls f(pos, vel, t).
+func (f ForceFunc) Acceleration(pos Point, vel Vector, t float64) Vector {
+	return f(pos, vel, t)
+}
+
+// Falloff determines how the strength of a force changes with distance.
+type Falloff int
+
+// Available falloff modes.
+const (
+	// FalloffNone applies the full strength of the force everywhere within
+	// its radius.
+	FalloffNone Falloff = iota
+
+	// FalloffLinear fades the force from full strength at its center to
+	// nothing at its radius.
+	FalloffLinear
+
+	// FalloffInverseSquare weakens the force with the square of the distance,
+	// like gravity. Strength is the acceleration at a distance of one unit;
+	// closer than that it's capped at full strength.
+	FalloffInverseSquare
+)
+
+// scale returns the fraction of a force's strength that applies at the given
+// distance from its center. A radius of zero means the force is unbounded.
+func (f Falloff) scale(dist, radius float64) float64 {
+	if radius > 0 && dist > radius {
+		return 0
+	}
+	switch f {
+	case FalloffLinear:
+		if radius <= 0 {
+			return 1
+		}
+		return 1 - dist/radius
+	case FalloffInverseSquare:
+		return 1 / math.Max(1, dist*dist)
+	default:
+		return 1
+	}
+}
+
+// Wind is a uniform flow that drags projectiles towards its velocity. The
+// drag coefficient determines how quickly projectiles pick up the wind's
+// velocity; higher values mean lighter projectiles.
+type Wind struct {
+	Velocity Vector
+	Drag     float64
+}
+
+// Acceleration implements Force.
+func (w Wind) Acceleration(_ Point, vel Vector, _ float64) Vector {
+	return w.Velocity.Sub(vel).Scale(w.Drag)
+}
+
+// Attractor pulls projectiles towards a point. A negative strength turns it
+// into a repulsor which pushes projectiles away instead.
+//
+// The radius limits the attractor's reach; zero means it reaches everywhere.
+type Attractor struct {
+	Position Point
+	Strength float64
+	Radius   float64
+	Falloff  Falloff
+}
+
+// Acceleration implements Force.
+func (a Attractor) Acceleration(pos Point, _ Vector, _ float64) Vector {
+	d := a.Position.Sub(pos)
+	dist := d.Length()
+	if dist < epsilon {
+		return Vector{}
+	}
+
+	return d.Scale(a.Strength * a.Falloff.scale(dist, a.Radius) / dist)
+}
+
+// Vortex swirls projectiles around an axis running through its center. A
+// positive strength swirls counter-clockwise when looking down the axis and
+// a negative strength swirls clockwise. A zero axis is treated as the Z axis,
+// which swirls in the XY plane.
+//
+// The radius limits the vortex's reach; zero means it reaches everywhere.
+type Vortex struct {
+	Center   Point
+	Axis     Vector
+	Strength float64
+	Radius   float64
+	Falloff  Falloff
+}
+
+// Acceleration implements Force.
+func (v Vortex) Acceleration(pos Point, _ Vector, _ float64) Vector {
+	axis := v.Axis.Normalize()
+	if axis == (Vector{}) {
+		axis = Vector{0, 0, 1}
+	}
+
+	// Offset from the center, with the component along the axis removed.
+	r := pos.Sub(v.Center)
+	r = r.Sub(axis.Scale(r.Dot(axis)))
+
+	dist := r.Length()
+	if dist < epsilon {
+		return Vector{}
+	}
+
+	// The tangent is perpendicular to both the axis and the offset.
+	return axis.Cross(r).Scale(v.Strength * v.Falloff.scale(dist, v.Radius) / dist)
+}
+
+// Turbulence buffets projectiles with smoothly varying, pseudorandom
+// accelerations. Use NewTurbulence to create one.
+//
+// Turbulence acts in all three dimensions. In 2D contexts the Z component can
+// safely be ignored.
+type Turbulence struct {
+	strength float64
+	scale    float64
+	speed    float64
+	noise    *perlin
+}
+
+// NewTurbulence creates a turbulence field.
+//
+// Strength is the maximum acceleration. Scale is the size of the swirls in
+// units; larger values produce broader, gentler variation. Speed is how
+// quickly the field changes over time. The seed determines the pattern, so
+// fields with the same seed behave identically.
+func NewTurbulence(strength, scale, speed float64, seed int64) *Turbulence {
+	return &Turbulence{
+		strength: strength,
+		scale:    math.Max(epsilon, scale),
+		speed:    speed,
+		noise:    newPerlin(seed),
+	}
+}
+
+// Acceleration implements Force.
+func (t *Turbulence) Acceleration(pos Point, _ Vector, time float64) Vector {
+	var (
+		x = pos.X/t.scale + time*t.speed
+		y = pos.Y/t.scale + time*t.speed
+		z = pos.Z / t.scale
+	)
+
+	// Sample the same noise field at distant offsets to get independent
+	// components.
+	return Vector{
+		X: t.noise.noise3(x, y, z) * t.strength,
+		Y: t.noise.noise3(x+31.416, y+47.853, z+12.793) * t.strength,
+		Z: t.noise.noise3(x+93.989, y+17.351, z+71.092) * t.strength,
+	}
+}
@@ -0,0 +1,96 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestWind(t *testing.T) {
+	p := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{0, 0, 0}, Vector{0, 0, 0})
+	p.AddForce(Wind{Velocity: Vector{10, 0, 0}, Drag: 2})
+
+	for i := 0; i < fps*10; i++ {
+		p.Update()
+	}
+
+	// Eventually a projectile travels with the wind.
+	if vel := p.Velocity(); !equal(vel.X, 10) || !equal(vel.Y, 0) {
+		t.Logf("Want: (%.2f, %.2f)", 10.0, 0.0)
+		t.Logf("Got:  (%.2f, %.2f)", vel.X, vel.Y)
+		t.Fatal("velocity unexpected")
+	}
+}
+
+func TestAttractor(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		force Attractor
+		want  Vector
+	}{
+		{"attract", Attractor{Position: Point{10, 0, 0}, Strength: 4}, Vector{4, 0, 0}},
+		{"repel", Attractor{Position: Point{10, 0, 0}, Strength: -4}, Vector{-4, 0, 0}},
+		{"out of reach", Attractor{Position: Point{10, 0, 0}, Strength: 4, Radius: 5}, Vector{0, 0, 0}},
+		{"linear", Attractor{Position: Point{10, 0, 0}, Strength: 4, Radius: 20, Falloff: FalloffLinear}, Vector{2, 0, 0}},
+		{"inverse square", Attractor{Position: Point{0, 10, 0}, Strength: 4, Falloff: FalloffInverseSquare}, Vector{0, 0.04, 0}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.force.Acceleration(Point{0, 0, 0}, Vector{}, 0)
+			if !equal(got.X, tc.want.X) || !equal(got.Y, tc.want.Y) || !equal(got.Z, tc.want.Z) {
+				t.Logf("Want: %+v", tc.want)
+				t.Logf("Got:  %+v", got)
+				t.Fatal("acceleration unexpected")
+			}
+		})
+	}
+}
+
+func TestVortex(t *testing.T) {
+	v := Vortex{Center: Point{0, 0, 0}, Strength: 3}
+
+	// Counter-clockwise around the Z axis.
+	got := v.Acceleration(Point{5, 0, 7}, Vector{}, 0)
+	if !equal(got.X, 0) || !equal(got.Y, 3) || !equal(got.Z, 0) {
+		t.Logf("Want: (%.2f, %.2f, %.2f)", 0.0, 3.0, 0.0)
+		t.Logf("Got:  (%.2f, %.2f, %.2f)", got.X, got.Y, got.Z)
+		t.Fatal("acceleration unexpected")
+	}
+}
+
+func TestTurbulence(t *testing.T) {
+	a := NewTurbulence(5, 10, 1, 7)
+	b := NewTurbulence(5, 10, 1, 7)
+
+	for i := 0; i < 100; i++ {
+		pos := Point{float64(i) * 1.3, float64(i) * 0.7, 0}
+		va := a.Acceleration(pos, Vector{}, float64(i)*0.1)
+		vb := b.Acceleration(pos, Vector{}, float64(i)*0.1)
+		if va != vb {
+			t.Fatal("turbulence with the same seed differs")
+		}
+		if math.Abs(va.X) > 5*1.1 || math.Abs(va.Y) > 5*1.1 || math.Abs(va.Z) > 5*1.1 {
+			t.Fatalf("turbulence %+v exceeds its strength", va)
+		}
+	}
+}
+
+func TestParticleSystemForces(t *testing.T) {
+	s := NewParticleSystem(10, 1)
+	s.AddEmitter(&Emitter{MinLifetime: 10, MaxLifetime: 10, Burst: 10})
+	s.AddForce(ForceFunc(func(Point, Vector, float64) Vector {
+		return Vector{0, 1, 0}
+	}))
+
+	for i := 0; i <= fps; i++ {
+		s.Update(FPS(fps))
+	}
+
+	for _, p := range s.Particles() {
+		if vel := p.Velocity(); !equal(vel.Y, 1) {
+			t.Logf("Want: %.2f", 1.0)
+			t.Logf("Got:  %.2f", vel.Y)
+			t.Fatal("particle velocity unexpected")
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package harmonica
+
+// This file defines a group of springs and projectiles that are updated
+// together and know when they've all come to rest. It's the core of anything
+// that drives animations on a timer, like Animator, which can stop its timer
+// once the group settles rather than redrawing the same frame forever.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    group := NewGroup(FPS(60))
+//    x := group.AddSpring(6.0, 0.5, 0)
+//    x.SetTarget(100)
+//
+//    // Update on every frame, until everything settles.
+//    for !group.Update() {
+//        draw(x.Position())
+//    }
+
+import "math"
+
+// DefaultThreshold is how close to its target, and how slow, a spring has to
+// be to count as settled.
+const DefaultThreshold = 0.01
+
+// Group is a set of springs and projectiles which are updated together.
+type Group struct {
+	deltaTime   float64
+	threshold   float64
+	springs     []*GroupSpring
+	projectiles []*GroupProjectile
+}
+
+// NewGroup creates a new group. It accepts the time delta to create springs
+// with, as from FPS.
+func NewGroup(deltaTime float64) *Group {
+	return &Group{
+		deltaTime: deltaTime,
+		threshold: DefaultThreshold,
+	}
+}
+
+// GroupSpring is a value animated by a spring in a Group.
+type GroupSpring struct {
+	spring   Spring
+	pos, vel float64
+	target   float64
+}
+
+// GroupProjectile is a projectile in a Group.
+type GroupProjectile struct {
+	*Projectile
+	done     func(*Projectile) bool
+	finished bool
+}
+
+// AddSpring adds a value animated by a spring with the given angular frequency
+// and damping ratio, as for NewSpring, starting at rest at the given position.
+func (g *Group) AddSpring(angularFrequency, dampingRatio, pos float64) *GroupSpring {
+	s := &GroupSpring{
+		spring: NewSpring(g.deltaTime, angularFrequency, dampingRatio),
+		pos:    pos,
+		target: pos,
+	}
+	g.springs = append(g.springs, s)
+	return s
+}
+
+// RemoveSpring stops updating a spring.
+func (g *Group) RemoveSpring(s *GroupSpring) {
+	for i, v := range g.springs {
+		if v == s {
+			g.springs = append(g.springs[:i], g.springs[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddProjectile adds a projectile to update. It keeps moving until done
+// reports true, such as when it hits the ground or leaves the screen, after
+// which it's no longer updated. If done is nil, it keeps moving until it's
+// removed.
+//
+// The projectile is updated with its own time delta, which should be the same
+// as the group's. Only Projectile.Update is called, so the embedded Projectile
+// of a RigidBody moves without rotating.
+func (g *Group) AddProjectile(p *Projectile, done func(*Projectile) bool) *GroupProjectile {
+	proj := &GroupProjectile{Projectile: p, done: done}
+	g.projectiles = append(g.projectiles, proj)
+	return proj
+}
+
+// RemoveProjectile stops updating a projectile.
+func (g *Group) RemoveProjectile(p *GroupProjectile) {
+	for i, v := range g.projectiles {
+		if v == p {
+			g.projectiles = append(g.projectiles[:i], g.projectiles[i+1:]...)
+			return
+		}
+	}
+}
+
+// Threshold returns how close to its target, and how slow, a spring has to be
+// to count as settled.
+func (g *Group) Threshold() float64 {
+	return g.threshold
+}
+
+// SetThreshold sets how close to its target, and how slow, a spring has to be
+// to count as settled. It defaults to DefaultThreshold.
+func (g *Group) SetThreshold(threshold float64) {
+	g.threshold = math.Max(0, threshold)
+}
+
+// Settled returns whether every spring is at rest at its target and every
+// projectile is done.
+func (g *Group) Settled() bool {
+	for _, s := range g.springs {
+		if !s.settled(g.threshold) {
+			return false
+		}
+	}
+	for _, p := range g.projectiles {
+		if !p.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Update advances every spring and projectile that's still moving by one time
+// step, and returns whether everything has now settled. Once it has, springs
+// are snapped exactly to their targets.
+func (g *Group) Update() bool {
+	for _, s := range g.springs {
+		if !s.settled(g.threshold) {
+			s.pos, s.vel = s.spring.Update(s.pos, s.vel, s.target)
+		}
+	}
+	for _, p := range g.projectiles {
+		if !p.Done() {
+			p.Update()
+		}
+	}
+
+	if !g.Settled() {
+		return false
+	}
+	for _, s := range g.springs {
+		s.pos, s.vel = s.target, 0
+	}
+	return true
+}
+
+// Position returns the current value of the spring.
+func (s *GroupSpring) Position() float64 {
+	return s.pos
+}
+
+// Velocity returns the current velocity of the spring.
+func (s *GroupSpring) Velocity() float64 {
+	return s.vel
+}
+
+// Target returns the value the spring is moving towards.
+func (s *GroupSpring) Target() float64 {
+	return s.target
+}
+
+// SetTarget sets the value the spring moves towards.
+func (s *GroupSpring) SetTarget(target float64) {
+	s.target = target
+}
+
+// SetPosition jumps the spring to a value, keeping its velocity.
+func (s *GroupSpring) SetPosition(pos float64) {
+	s.pos = pos
+}
+
+// SetVelocity sets the velocity of the spring, such as to give it a nudge.
+func (s *GroupSpring) SetVelocity(vel float64) {
+	s.vel = vel
+}
+
+// settled returns whether the spring is at rest at its target.
+func (s *GroupSpring) settled(threshold float64) bool {
+	return math.Abs(s.pos-s.target) < threshold && math.Abs(s.vel) < threshold
+}
+
+// Done returns whether the projectile has finished moving. Once done reports
+// true it isn't called again.
+func (p *GroupProjectile) Done() bool {
+	if !p.finished && p.done != nil && p.done(p.Projectile) {
+		p.finished = true
+	}
+	return p.finished
+}
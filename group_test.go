@@ -0,0 +1,59 @@
+package harmonica_test
+
+import (
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestGroupSettles(t *testing.T) {
+	g := NewGroup(FPS(fps))
+	a := g.AddSpring(8, 0.5, 0)
+	b := g.AddSpring(6, 1, 5)
+	a.SetTarget(10)
+
+	if g.Settled() {
+		t.Fatal("expected group with a moving spring not to be settled")
+	}
+
+	var frames int
+	for !g.Update() {
+		if frames++; frames > fps*60 {
+			t.Fatal("group never settled")
+		}
+	}
+	if a.Position() != 10 || a.Velocity() != 0 || b.Position() != 5 {
+		t.Fatalf("expected springs to snap to their targets, got %.4f and %.4f", a.Position(), b.Position())
+	}
+
+	// Removed springs no longer hold the group up.
+	b.SetTarget(20)
+	g.RemoveSpring(b)
+	if !g.Settled() {
+		t.Fatal("expected removed spring to be ignored")
+	}
+}
+
+func TestGroupProjectile(t *testing.T) {
+	var (
+		g     = NewGroup(FPS(fps))
+		calls int
+	)
+	p := g.AddProjectile(NewProjectile(FPS(fps), Point{}, Vector{10, 0, 0}, Vector{}), func(p *Projectile) bool {
+		calls++
+		return p.Position().X >= 5
+	})
+
+	for !g.Update() {
+	}
+	if x := p.Position().X; x < 5 || x > 5.2 {
+		t.Fatalf("expected projectile to stop at 5, got %.2f", x)
+	}
+
+	// Once done, the projectile stays put and done isn't asked again.
+	before := calls
+	g.Update()
+	if !p.Done() || calls != before || p.Position().X > 5.2 {
+		t.Fatal("expected finished projectile to be left alone")
+	}
+}
@@ -0,0 +1,229 @@
+package harmonica
+
+// This file defines an N-body gravity simulator, where every body pulls on
+// every other body. It's integrated with the leapfrog method, which conserves
+// energy well over long periods, so orbits stay put rather than slowly
+// spiraling in or out.
+//
+// Example usage:
+//
+//    // Run once to initialize a sun with a planet in a circular orbit.
+//    sun := Body{Position: Point{40, 12, 0}, Mass: 1000}
+//    planet := CircularOrbit(sun, 10, 0, 1, 1)
+//    sim := NewNBody(FPS(60), 1, 0.1, sun, planet)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        sim.Update()
+//        for _, b := range sim.Bodies {
+//            draw(b.Position)
+//        }
+//    })
+//
+// For background on the integrator see:
+// https://en.wikipedia.org/wiki/Leapfrog_integration
+
+import "math"
+
+// Body is a point mass in an N-body simulation.
+type Body struct {
+	Position Point
+	Velocity Vector
+	Mass     float64
+}
+
+// NBody simulates bodies moving under their mutual gravitational attraction.
+//
+// Bodies may be read between updates, and their velocities changed. After
+// changing their positions or masses in place, or changing G or Softening,
+// call Reset before the next update.
+type NBody struct {
+	Bodies []Body
+
+	// G is the gravitational constant. For animation purposes it's easiest
+	// to pick masses and distances that look good and tune G to taste.
+	G float64
+
+	// Softening is a small distance added to the separation between bodies,
+	// which keeps the pull between bodies that pass very close to one another
+	// from blowing up.
+	Softening float64
+
+	deltaTime float64
+
+	// Accelerations at the end of the last step, which are reused at the start
+	// of the next one while they're fresh.
+	acc   []Vector
+	fresh bool
+}
+
+// NewNBody creates a new N-body simulation. It accepts a time delta, the
+// gravitational constant, the softening distance, and the bodies to simulate.
+func NewNBody(deltaTime, g, softening float64, bodies ...Body) *NBody {
+	return &NBody{
+		Bodies:    bodies,
+		G:         g,
+		Softening: softening,
+		deltaTime: deltaTime,
+	}
+}
+
+// Add adds a body to the simulation and returns its index.
+func (n *NBody) Add(b Body) int {
+	n.Bodies = append(n.Bodies, b)
+	n.fresh = false
+	return len(n.Bodies) - 1
+}
+
+// Reset tells the simulation that bodies have been changed, so accelerations
+// from the last update can't be reused.
+func (n *NBody) Reset() {
+	n.fresh = false
+}
+
+// Update advances the simulation by one time step using kick-drift-kick
+// leapfrog integration.
+func (n *NBody) Update() {
+	dt := n.deltaTime
+
+	// The accelerations at the end of the last step are the ones at the start
+	// of this one, so they're only computed again if bodies have changed.
+	if !n.fresh || len(n.acc) != len(n.Bodies) {
+		n.accelerate()
+	}
+
+	for i := range n.Bodies {
+		b := &n.Bodies[i]
+		b.Velocity = b.Velocity.Add(n.acc[i].Scale(dt / 2))
+		b.Position = b.Position.Add(b.Velocity.Scale(dt))
+	}
+
+	n.accelerate()
+
+	for i := range n.Bodies {
+		b := &n.Bodies[i]
+		b.Velocity = b.Velocity.Add(n.acc[i].Scale(dt / 2))
+	}
+	n.fresh = true
+}
+
+// accelerate computes the gravitational acceleration of every body.
+func (n *NBody) accelerate() {
+	if cap(n.acc) < len(n.Bodies) {
+		n.acc = make([]Vector, len(n.Bodies))
+	}
+	n.acc = n.acc[:len(n.Bodies)]
+	for i := range n.acc {
+		n.acc[i] = Vector{}
+	}
+
+	soft := n.Softening * n.Softening
+	for i := range n.Bodies {
+		for j := i + 1; j < len(n.Bodies); j++ {
+			d := n.Bodies[j].Position.Sub(n.Bodies[i].Position)
+			distSq := d.LengthSquared() + soft
+			if distSq < epsilon {
+				continue
+			}
+			f := n.G / (distSq * math.Sqrt(distSq))
+			n.acc[i] = n.acc[i].Add(d.Scale(f * n.Bodies[j].Mass))
+			n.acc[j] = n.acc[j].Sub(d.Scale(f * n.Bodies[i].Mass))
+		}
+	}
+}
+
+// Energy returns the total kinetic and potential energy of the system. In
+// a closed system it stays nearly constant, which makes it handy for checking
+// that the time step is small enough.
+func (n *NBody) Energy() float64 {
+	var e float64
+	soft := n.Softening * n.Softening
+	for i, a := range n.Bodies {
+		e += 0.5 * a.Mass * a.Velocity.LengthSquared()
+		for _, b := range n.Bodies[i+1:] {
+			e -= n.G * a.Mass * b.Mass / math.Sqrt(a.Position.DistanceSquared(b.Position)+soft)
+		}
+	}
+	return e
+}
+
+// OrbitalElements are the Keplerian elements that describe an orbit. Angles
+// are in radians.
+type OrbitalElements struct {
+	// SemiMajorAxis is half the orbit's longest diameter.
+	SemiMajorAxis float64
+
+	// Eccentricity is how stretched the orbit is, from 0 for a circle towards
+	// 1 for a very long ellipse.
+	Eccentricity float64
+
+	// Inclination is the tilt of the orbit away from the XY plane.
+	Inclination float64
+
+	// LongitudeOfAscendingNode is where the orbit crosses the XY plane going
+	// upwards, measured from the X axis.
+	LongitudeOfAscendingNode float64
+
+	// ArgumentOfPeriapsis is where the closest approach happens, measured
+	// from the ascending node in the plane of the orbit.
+	ArgumentOfPeriapsis float64
+
+	// TrueAnomaly is where along the orbit the body starts, measured from the
+	// closest approach.
+	TrueAnomaly float64
+}
+
+// Orbit returns a body of the given mass placed in orbit around a central
+// body, as described by the orbital elements, for the gravitational constant
+// g. With zero inclination the orbit lies in the XY plane and runs
+// counter-clockwise.
+//
+// Only the central body's gravity is taken into account, so the orbit is
+// exact for two bodies and approximate when other bodies are nearby.
+// Eccentricities of 1 and above don't describe closed orbits and are clamped
+// to just below 1.
+func Orbit(central Body, el OrbitalElements, mass, g float64) Body {
+	var (
+		e  = math.Max(0, math.Min(1-1e-9, el.Eccentricity))
+		mu = g * (central.Mass + mass)
+		p  = el.SemiMajorAxis * (1 - e*e) // semi-latus rectum
+
+		sinNu, cosNu = math.Sincos(el.TrueAnomaly)
+		r            = p / (1 + e*cosNu)
+		speed        = math.Sqrt(mu / p)
+	)
+
+	// Position and velocity in the plane of the orbit, with the X axis
+	// pointing at the closest approach.
+	pos := Vector{r * cosNu, r * sinNu, 0}
+	vel := Vector{-speed * sinNu, speed * (e + cosNu), 0}
+
+	// Rotate the orbit's plane into place.
+	orient := func(v Vector) Vector {
+		v = v.Rotate(el.ArgumentOfPeriapsis)
+		v = v.RotateAround(Vector{1, 0, 0}, el.Inclination)
+		return v.Rotate(el.LongitudeOfAscendingNode)
+	}
+
+	return Body{
+		Position: central.Position.Add(orient(pos)),
+		Velocity: central.Velocity.Add(orient(vel)),
+		Mass:     mass,
+	}
+}
+
+// CircularOrbit returns a body of the given mass in a counter-clockwise
+// circular orbit in the XY plane around a central body, at the given radius
+// and starting angle, for the gravitational constant g.
+func CircularOrbit(central Body, radius, angle, mass, g float64) Body {
+	return Orbit(central, OrbitalElements{
+		SemiMajorAxis: radius,
+		TrueAnomaly:   angle,
+	}, mass, g)
+}
+
+// OrbitalPeriod returns the time it takes to complete an orbit with the given
+// semi-major axis around a central body, for the gravitational constant g.
+func OrbitalPeriod(centralMass, mass, semiMajorAxis, g float64) float64 {
+	return 2 * math.Pi * math.Sqrt(semiMajorAxis*semiMajorAxis*semiMajorAxis/(g*(centralMass+mass)))
+}
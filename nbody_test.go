@@ -0,0 +1,125 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestCircularOrbit(t *testing.T) {
+	const (
+		g      = 1.0
+		radius = 10.0
+	)
+	sun := Body{Position: Point{0, 0, 0}, Mass: 1000}
+	planet := CircularOrbit(sun, radius, 0, 1e-6, g)
+
+	if !equal(planet.Position.X, radius) || !equal(planet.Velocity.Y, math.Sqrt(g*sun.Mass/radius)) {
+		t.Fatalf("orbiting body unexpected: %+v", planet)
+	}
+
+	sim := NewNBody(FPS(fps), g, 0, sun, planet)
+	period := OrbitalPeriod(sun.Mass, planet.Mass, radius, g)
+	start := sim.Energy()
+
+	steps := int(math.Round(period * fps))
+	for i := 0; i < steps; i++ {
+		sim.Update()
+		if d := sim.Bodies[1].Position.Distance(sim.Bodies[0].Position); math.Abs(d-radius) > 0.01 {
+			t.Fatalf("orbit drifted to radius %.3f at step %d", d, i)
+		}
+	}
+
+	// Back where it started after one period.
+	if d := sim.Bodies[1].Position.Distance(Point{radius, 0, 0}); d > 0.1 {
+		t.Fatalf("expected planet back at its start, got %+v", sim.Bodies[1].Position)
+	}
+	if e := sim.Energy(); math.Abs((e-start)/start) > 1e-6 {
+		t.Fatalf("energy drifted from %g to %g", start, e)
+	}
+}
+
+func TestEllipticalOrbit(t *testing.T) {
+	const g = 1.0
+	sun := Body{Position: Point{5, 5, 0}, Velocity: Vector{1, 0, 0}, Mass: 1000}
+	el := OrbitalElements{
+		SemiMajorAxis:            10,
+		Eccentricity:             0.5,
+		Inclination:              math.Pi / 4,
+		LongitudeOfAscendingNode: math.Pi / 3,
+		ArgumentOfPeriapsis:      math.Pi / 6,
+	}
+	planet := Orbit(sun, el, 0, g)
+
+	// Starting at the closest approach, a(1-e) away.
+	if d := planet.Position.Distance(sun.Position); !equal(d, 5) {
+		t.Fatalf("periapsis distance %.3f, want %.3f", d, 5.0)
+	}
+
+	// The vis-viva equation relates speed to distance.
+	rel := planet.Velocity.Sub(sun.Velocity).Length()
+	if want := math.Sqrt(g * sun.Mass * (2/5.0 - 1/10.0)); !equal(rel, want) {
+		t.Fatalf("periapsis speed %.3f, want %.3f", rel, want)
+	}
+
+	sim := NewNBody(FPS(fps)/4, g, 0, sun, planet)
+	var farthest float64
+	for i := 0; i < int(OrbitalPeriod(sun.Mass, 0, 10, g)*fps*4); i++ {
+		sim.Update()
+		farthest = math.Max(farthest, sim.Bodies[1].Position.Distance(sim.Bodies[0].Position))
+	}
+
+	// Reaching a(1+e) at its farthest.
+	if math.Abs(farthest-15) > 0.05 {
+		t.Fatalf("apoapsis distance %.3f, want %.3f", farthest, 15.0)
+	}
+}
+
+func TestNBodySoftening(t *testing.T) {
+	a := Body{Position: Point{0, 0, 0}, Mass: 1}
+	b := Body{Position: Point{0, 0, 0}, Mass: 1}
+	sim := NewNBody(FPS(fps), 1, 0.5, a, b)
+	sim.Update()
+
+	for _, body := range sim.Bodies {
+		if math.IsNaN(body.Position.X) || math.IsInf(body.Velocity.X, 0) {
+			t.Fatalf("coincident bodies blew up: %+v", body)
+		}
+	}
+}
+
+func TestNBodyReset(t *testing.T) {
+	var (
+		sun    = Body{Position: Point{0, 0, 0}, Mass: 1000}
+		planet = CircularOrbit(sun, 10, 0, 1, 1)
+		moon   = CircularOrbit(planet, 1, 0, 0.01, 1)
+	)
+	sim := NewNBody(FPS(fps), 1, 0, sun, planet)
+	sim.Update()
+
+	// Moving a body and resetting steps the same as starting over.
+	sim.Bodies[1].Position = Point{0, 20, 0}
+	sim.Reset()
+	want := NewNBody(FPS(fps), 1, 0, append([]Body(nil), sim.Bodies...)...)
+	sim.Update()
+	want.Update()
+	if sim.Bodies[1] != want.Bodies[1] {
+		t.Logf("Want: %+v", want.Bodies[1])
+		t.Logf("Got:  %+v", sim.Bodies[1])
+		t.Fatal("reset simulation diverged")
+	}
+
+	// Adding a body doesn't need a reset.
+	sim.Add(moon)
+	want = NewNBody(FPS(fps), 1, 0, append([]Body(nil), sim.Bodies...)...)
+	sim.Update()
+	want.Update()
+	for i := range want.Bodies {
+		if sim.Bodies[i] != want.Bodies[i] {
+			t.Logf("Want: %+v", want.Bodies[i])
+			t.Logf("Got:  %+v", sim.Bodies[i])
+			t.Fatalf("body %d diverged after adding a body", i)
+		}
+	}
+}
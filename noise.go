@@ -0,0 +1,115 @@
+package harmonica
+
+// This file defines seeded gradient noise, used wherever we need smooth,
+// coherent randomness. It's a port of Ken Perlin's improved noise with the
+// permutation table shuffled by a seed, so results are deterministic.
+//
+// For background on the algorithm see:
+// https://mrl.cs.nyu.edu/~perlin/noise/
+
+import (
+	"math"
+	"math/rand"
+)
+
+// perlin is a seeded 3D gradient noise generator.
+type perlin struct {
+	perm [512]uint8
+}
+
+// newPerlin returns a noise generator with a permutation table derived from
+// the given seed.
+func newPerlin(seed int64) *perlin {
+	var n perlin
+	r := rand.New(rand.NewSource(seed))
+	for i, v := range r.Perm(256) {
+		n.perm[i] = uint8(v)
+		n.perm[i+256] = uint8(v)
+	}
+	return &n
+}
+
+// noise3 returns smooth noise at the given coordinates in the range of
+// roughly [-1, 1]. It's zero at every integer lattice point.
+func (n *perlin) noise3(x, y, z float64) float64 {
+	var (
+		fx = math.Floor(x)
+		fy = math.Floor(y)
+		fz = math.Floor(z)
+
+		xi = int(fx) & 255
+		yi = int(fy) & 255
+		zi = int(fz) & 255
+	)
+
+	x -= fx
+	y -= fy
+	z -= fz
+
+	var (
+		u = fade(x)
+		v = fade(y)
+		w = fade(z)
+
+		p  = &n.perm
+		a  = int(p[xi]) + yi
+		aa = int(p[a]) + zi
+		ab = int(p[a+1]) + zi
+		b  = int(p[xi+1]) + yi
+		ba = int(p[b]) + zi
+		bb = int(p[b+1]) + zi
+	)
+
+	return lerp(
+		lerp(
+			lerp(grad(p[aa], x, y, z), grad(p[ba], x-1, y, z), u),
+			lerp(grad(p[ab], x, y-1, z), grad(p[bb], x-1, y-1, z), u),
+			v,
+		),
+		lerp(
+			lerp(grad(p[aa+1], x, y, z-1), grad(p[ba+1], x-1, y, z-1), u),
+			lerp(grad(p[ab+1], x, y-1, z-1), grad(p[bb+1], x-1, y-1, z-1), u),
+			v,
+		),
+		w,
+	)
+}
+
+// fade eases coordinate values so that they'll ease towards integral values,
+// using 6t⁵ - 15t⁴ + 10t³.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + t*(b-a)
+}
+
+// grad returns the dot product of a pseudorandom gradient vector, picked by
+// hash, and the distance vector (x, y, z).
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+
+	u := y
+	if h < 8 {
+		u = x
+	}
+
+	var v float64
+	switch {
+	case h < 4:
+		v = y
+	case h == 12 || h == 14:
+		v = x
+	default:
+		v = z
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
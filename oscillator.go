@@ -0,0 +1,187 @@
+package harmonica
+
+// This file defines continuous, never-ending motion: a low-frequency
+// oscillator (LFO) with a handful of classic waveforms, and a spring whose
+// target is driven back and forth, which shows resonance.
+//
+// Example usage:
+//
+//    // Run once to initialize a cursor that pulses twice a second.
+//    pulse := NewOscillator(FPS(60), Sine, 2, 1, 0)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        brightness := 0.5 + pulse.Update()*0.5
+//    })
+//
+// Like NewSpring, oscillators advance by a fixed time delta on every update,
+// so use FPS or your engine's time delta.
+
+import "math"
+
+// Waveform is the shape of an oscillator's wave.
+type Waveform int
+
+// Available waveforms. They all range from -1 to 1 and start at 0, rising,
+// except for Square, which starts at 1.
+const (
+	// Sine is a smooth wave, good for breathing and bobbing.
+	Sine Waveform = iota
+
+	// Triangle ramps linearly up and down.
+	Triangle
+
+	// Square flips between its high and low values, good for blinking.
+	Square
+
+	// Sawtooth ramps linearly up, then drops back down.
+	Sawtooth
+)
+
+// Value returns the value of the waveform at the given point in its cycle,
+// where 1 is a full cycle.
+func (w Waveform) Value(cycles float64) float64 {
+	x := cycles - math.Floor(cycles)
+	switch w {
+	case Triangle:
+		return 1 - 4*math.Abs(frac(x+0.25)-0.5)
+	case Square:
+		if x < 0.5 {
+			return 1
+		}
+		return -1
+	case Sawtooth:
+		return 2*frac(x+0.5) - 1
+	default:
+		return math.Sin(2 * math.Pi * x)
+	}
+}
+
+// frac returns the fractional part of x, in [0, 1).
+func frac(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+// Oscillator produces a periodic wave.
+type Oscillator struct {
+	waveform  Waveform
+	frequency float64
+	amplitude float64
+	cycles    float64 // elapsed cycles, including the phase
+	deltaTime float64
+}
+
+// NewOscillator creates a new oscillator. It accepts a time delta, the shape
+// of the wave, its frequency in Hz (cycles per second), its amplitude and its
+// starting phase in radians.
+func NewOscillator(deltaTime float64, waveform Waveform, frequency, amplitude, phase float64) *Oscillator {
+	return &Oscillator{
+		waveform:  waveform,
+		frequency: frequency,
+		amplitude: amplitude,
+		cycles:    phase / (2 * math.Pi),
+		deltaTime: deltaTime,
+	}
+}
+
+// Update advances the oscillator by one time step and returns its new value.
+func (o *Oscillator) Update() float64 {
+	// Keep the cycle count small so precision doesn't degrade over time.
+	o.cycles = frac(o.cycles + o.frequency*o.deltaTime)
+	return o.Value()
+}
+
+// Value returns the current value of the oscillator.
+func (o *Oscillator) Value() float64 {
+	return o.waveform.Value(o.cycles) * o.amplitude
+}
+
+// Phase returns the current phase of the oscillator in radians, from 0 to 2π.
+func (o *Oscillator) Phase() float64 {
+	return frac(o.cycles) * 2 * math.Pi
+}
+
+// SetFrequency sets the frequency of the oscillator in Hz. The wave continues
+// smoothly from where it is, without jumping.
+func (o *Oscillator) SetFrequency(frequency float64) {
+	o.frequency = frequency
+}
+
+// SetAmplitude sets the amplitude of the oscillator.
+func (o *Oscillator) SetAmplitude(amplitude float64) {
+	o.amplitude = amplitude
+}
+
+// SetWaveform sets the shape of the oscillator's wave.
+func (o *Oscillator) SetWaveform(waveform Waveform) {
+	o.waveform = waveform
+}
+
+// DrivenSpring is a damped spring whose target is pushed back and forth
+// sinusoidally around the equilibrium position, so it never comes to rest.
+//
+// When the drive's angular frequency is close to the spring's, the spring
+// resonates: small drive amplitudes produce large swings, especially with
+// little damping.
+type DrivenSpring struct {
+	spring                Spring
+	angularFrequency      float64
+	dampingRatio          float64
+	driveAngularFrequency float64
+	driveAmplitude        float64
+	time                  float64
+	deltaTime             float64
+}
+
+// NewDrivenSpring creates a new driven spring. It accepts a time delta, the
+// angular frequency and damping ratio, as NewSpring does, plus the angular
+// frequency of the drive, also in radians per second, and its amplitude, which
+// is how far the spring's target moves to either side. Note that this differs
+// from NewOscillator, which takes its frequency in Hz: multiply Hz by 2π to
+// get an angular frequency.
+func NewDrivenSpring(deltaTime, angularFrequency, dampingRatio, driveAngularFrequency, driveAmplitude float64) *DrivenSpring {
+	return &DrivenSpring{
+		spring:                NewSpring(deltaTime, angularFrequency, dampingRatio),
+		angularFrequency:      math.Max(0, angularFrequency),
+		dampingRatio:          math.Max(0, dampingRatio),
+		driveAngularFrequency: driveAngularFrequency,
+		driveAmplitude:        driveAmplitude,
+		deltaTime:             deltaTime,
+	}
+}
+
+// Update updates position and velocity values, driven around the given
+// equilibrium position.
+func (d *DrivenSpring) Update(pos, vel, equilibriumPos float64) (newPos, newVel float64) {
+	// Holding the drive at its midpoint value over the step keeps the
+	// integration accurate to second order.
+	mid := d.time + d.deltaTime/2
+	target := equilibriumPos + d.driveAmplitude*math.Sin(d.driveAngularFrequency*mid)
+
+	d.time += d.deltaTime
+
+	// Wrap time around whole drive periods so precision doesn't degrade.
+	if d.driveAngularFrequency > 0 {
+		period := 2 * math.Pi / d.driveAngularFrequency
+		d.time = math.Mod(d.time, period)
+	}
+
+	return d.spring.Update(pos, vel, target)
+}
+
+// SteadyStateAmplitude returns how far the spring swings to either side of
+// equilibrium once the effects of its starting position and velocity have
+// died away. It peaks near the spring's angular frequency.
+func (d *DrivenSpring) SteadyStateAmplitude() float64 {
+	var (
+		w  = d.angularFrequency
+		wd = d.driveAngularFrequency
+		a  = w*w - wd*wd
+		b  = 2 * d.dampingRatio * w * wd
+	)
+	den := math.Sqrt(a*a + b*b)
+	if den < epsilon {
+		return math.Inf(1)
+	}
+	return math.Abs(d.driveAmplitude) * w * w / den
+}
@@ -0,0 +1,84 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestWaveforms(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		waveform Waveform
+		want     [4]float64 // at 0, ¼, ½ and ¾ of a cycle
+	}{
+		{"sine", Sine, [4]float64{0, 1, 0, -1}},
+		{"triangle", Triangle, [4]float64{0, 1, 0, -1}},
+		{"square", Square, [4]float64{1, 1, -1, -1}},
+		{"sawtooth", Sawtooth, [4]float64{0, 0.5, -1, -0.5}},
+	} {
+		for i, want := range tc.want {
+			if got := tc.waveform.Value(float64(i) / 4); !equal(got, want) {
+				t.Fatalf("%s at %d/4 cycle: got %.2f, want %.2f", tc.name, i, got, want)
+			}
+		}
+	}
+}
+
+func TestOscillator(t *testing.T) {
+	o := NewOscillator(FPS(fps), Sine, 2, 3, math.Pi/2)
+	if !equal(o.Value(), 3) {
+		t.Fatalf("expected oscillator to start at its peak, got %.2f", o.Value())
+	}
+
+	// Half a cycle at 2 Hz is a quarter of a second.
+	for i := 0; i < fps/4; i++ {
+		o.Update()
+	}
+	if !equal(o.Value(), -3) {
+		t.Fatalf("expected oscillator to be at its trough, got %.2f", o.Value())
+	}
+
+	// It keeps going indefinitely.
+	for i := 0; i < fps*60*60; i++ {
+		o.Update()
+	}
+	if p := o.Phase(); p < 0 || p >= 2*math.Pi {
+		t.Fatalf("phase out of range: %.2f", p)
+	}
+}
+
+func TestDrivenSpringResonance(t *testing.T) {
+	const (
+		frequency = 6.0
+		damping   = 0.1
+	)
+
+	amplitude := func(driveAngularFrequency float64) float64 {
+		s := NewDrivenSpring(FPS(fps), frequency, damping, driveAngularFrequency, 1)
+		var pos, vel, peak float64
+		for i := 0; i < fps*30; i++ {
+			pos, vel = s.Update(pos, vel, 0)
+			if i > fps*20 {
+				peak = math.Max(peak, math.Abs(pos))
+			}
+		}
+		return peak
+	}
+
+	resonant := amplitude(frequency)
+	slow := amplitude(frequency / 4)
+	fast := amplitude(frequency * 4)
+
+	if resonant < 4 || resonant < slow*3 || resonant < fast*3 {
+		t.Fatalf("expected resonance, got %.2f at resonance, %.2f below and %.2f above", resonant, slow, fast)
+	}
+
+	want := NewDrivenSpring(FPS(fps), frequency, damping, frequency, 1).SteadyStateAmplitude()
+	if math.Abs(resonant-want)/want > 0.05 {
+		t.Logf("Want: %.2f", want)
+		t.Logf("Got:  %.2f", resonant)
+		t.Fatal("resonant amplitude unexpected")
+	}
+}
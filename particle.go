@@ -0,0 +1,249 @@
+package harmonica
+
+// This file defines a particle system built on top of Projectile. Emitters
+// spawn particles at a steady rate or in bursts, particles age and die, and
+// dead particles are recycled so that a running system doesn't allocate.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    particles := NewParticleSystem(500, 1)
+//    particles.AddEmitter(&Emitter{
+//        Position:     Point{40, 20, 0},
+//        Direction:    Vector{0, -1, 0},
+//        Spread:       math.Pi / 6,
+//        MinSpeed:     10,
+//        MaxSpeed:     20,
+//        MinLifetime:  1,
+//        MaxLifetime:  2,
+//        Rate:         30,
+//        Acceleration: TerminalGravity,
+//        Planar:       true,
+//    })
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        particles.Update(FPS(60))
+//        for _, p := range particles.Particles() {
+//            draw(p.Position())
+//        }
+//    })
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Particle is a single particle in a ParticleSystem. It's a Projectile with an
+// age and a lifetime, both in seconds.
+type Particle struct {
+	Projectile
+	Age      float64
+	Lifetime float64
+}
+
+// Progress returns how far along the particle is in its life, from 0 when
+// it's born to 1 when it dies. It's useful for fading particles out.
+func (p Particle) Progress() float64 {
+	if p.Lifetime <= 0 {
+		return 1
+	}
+	return math.Min(1, p.Age/p.Lifetime)
+}
+
+// Emitter describes how and where particles are spawned.
+//
+// Particles leave the emitter in a cone around Direction, with Spread being
+// the cone's half-angle in radians. A Spread of zero emits in a straight line
+// and a Spread of π emits in all directions. Speeds and lifetimes are picked
+// at random from their ranges.
+type Emitter struct {
+	Position  Point
+	Direction Vector
+	Spread    float64
+
+	MinSpeed, MaxSpeed       float64
+	MinLifetime, MaxLifetime float64
+
+	// Rate is the number of particles emitted per second.
+	Rate float64
+
+	// Burst is the number of particles to emit all at once on the next
+	// update. It's reset to zero once the burst has been emitted.
+	Burst int
+
+	// Acceleration is the constant acceleration applied to every particle,
+	// such as Gravity or TerminalGravity.
+	Acceleration Vector
+
+	// Planar restricts emission to the XY plane, which is what you want for
+	// 2D contexts.
+	Planar bool
+
+	// Fractional particles owed from the emission rate.
+	accumulator float64
+}
+
+// ParticleSystem manages particles spawned by a set of emitters.
+//
+// Particles live in a pool with a fixed capacity, which is allocated up front.
+// Dead particles are recycled to make room for new ones and once the pool is
+// full any further particles are dropped, so updating a system never
+// allocates.
+type ParticleSystem struct {
+	emitters  []*Emitter
+	forces    []Force
+	particles []Particle
+	live      int
+	rand      *rand.Rand
+}
+
+// NewParticleSystem creates a new particle system which holds at most the
+// given number of particles. Randomness is derived from the seed, so systems
+// created with the same seed and updated in the same way behave identically.
+func NewParticleSystem(capacity int, seed int64) *ParticleSystem {
+	return &ParticleSystem{
+		particles: make([]Particle, capacity),
+		rand:      rand.New(rand.NewSource(seed)),
+	}
+}
+
+// AddEmitter adds an emitter to the system. Emitters may be changed between
+// updates to move them around or alter their output.
+func (s *ParticleSystem) AddEmitter(e *Emitter) {
+	s.emitters = append(s.emitters, e)
+}
+
+// RemoveEmitter removes an emitter from the system. Particles it already
+// emitted live on.
+func (s *ParticleSystem) RemoveEmitter(e *Emitter) {
+	for i, v := range s.emitters {
+		if v == e {
+			s.emitters = append(s.emitters[:i], s.emitters[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddForce layers a force field on every particle in the system, in addition
+// to the acceleration particles get from their emitter.
+func (s *ParticleSystem) AddForce(f Force) {
+	s.forces = append(s.forces, f)
+}
+
+// ClearForces removes all force fields from the system.
+func (s *ParticleSystem) ClearForces() {
+	s.forces = nil
+}
+
+// Update advances all particles by the given time delta, retires particles
+// that have reached the end of their lifetime and emits new ones.
+func (s *ParticleSystem) Update(deltaTime float64) {
+	for i := 0; i < s.live; {
+		p := &s.particles[i]
+		p.Age += deltaTime
+		if p.Age >= p.Lifetime {
+			// Swap the dead particle out of the live range so its slot can be
+			// reused.
+			s.live--
+			s.particles[i], s.particles[s.live] = s.particles[s.live], s.particles[i]
+			continue
+		}
+		p.step(deltaTime, s.forces)
+		i++
+	}
+
+	for _, e := range s.emitters {
+		e.accumulator += e.Rate * deltaTime
+		n := int(e.accumulator)
+		e.accumulator -= float64(n)
+		n += e.Burst
+		e.Burst = 0
+
+		for ; n > 0; n-- {
+			s.emit(e, deltaTime)
+		}
+	}
+}
+
+// Particles returns the live particles. The returned slice is only valid
+// until the next call to Update.
+func (s *ParticleSystem) Particles() []Particle {
+	return s.particles[:s.live]
+}
+
+// Len returns the number of live particles.
+func (s *ParticleSystem) Len() int {
+	return s.live
+}
+
+// Cap returns the maximum number of particles the system can hold.
+func (s *ParticleSystem) Cap() int {
+	return len(s.particles)
+}
+
+// Reset kills all particles.
+func (s *ParticleSystem) Reset() {
+	s.live = 0
+}
+
+// emit spawns a single particle from the given emitter, if there's room.
+func (s *ParticleSystem) emit(e *Emitter, deltaTime float64) {
+	if s.live == len(s.particles) {
+		return
+	}
+
+	speed := e.MinSpeed + s.rand.Float64()*(e.MaxSpeed-e.MinSpeed)
+	dir := s.coneDirection(e)
+
+	p := &s.particles[s.live]
+	s.live++
+
+	p.Projectile = Projectile{
+		pos:       e.Position,
+		vel:       dir.Scale(speed),
+		acc:       e.Acceleration,
+		mass:      1,
+		deltaTime: deltaTime,
+	}
+	p.Age = 0
+	p.Lifetime = e.MinLifetime + s.rand.Float64()*(e.MaxLifetime-e.MinLifetime)
+}
+
+// coneDirection returns a random unit vector within the emitter's cone.
+func (s *ParticleSystem) coneDirection(e *Emitter) Vector {
+	axis := e.Direction
+	if e.Planar {
+		axis.Z = 0
+	}
+	axis = axis.Normalize()
+	if axis == (Vector{}) {
+		axis = Vector{0, 1, 0}
+	}
+
+	spread := math.Max(0, math.Min(math.Pi, e.Spread))
+
+	if e.Planar {
+		return axis.Rotate((s.rand.Float64()*2 - 1) * spread)
+	}
+
+	// Pick a direction uniformly distributed over the cone's cap on the unit
+	// sphere, then orient it around the axis.
+	var (
+		cosTheta = 1 - s.rand.Float64()*(1-math.Cos(spread))
+		sinTheta = math.Sqrt(math.Max(0, 1-cosTheta*cosTheta))
+		phi      = s.rand.Float64() * 2 * math.Pi
+	)
+
+	// Build an orthonormal basis (u, w) perpendicular to the axis.
+	helper := Vector{1, 0, 0}
+	if math.Abs(axis.X) > 0.9 {
+		helper = Vector{0, 1, 0}
+	}
+	u := axis.Cross(helper).Normalize()
+	w := axis.Cross(u)
+
+	return axis.Scale(cosTheta).
+		Add(u.Scale(sinTheta * math.Cos(phi))).
+		Add(w.Scale(sinTheta * math.Sin(phi)))
+}
@@ -0,0 +1,159 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestParticleSystemRate(t *testing.T) {
+	s := NewParticleSystem(1000, 1)
+	s.AddEmitter(&Emitter{
+		Direction:   Vector{0, 1, 0},
+		MinSpeed:    1,
+		MaxSpeed:    1,
+		MinLifetime: 10,
+		MaxLifetime: 10,
+		Rate:        30,
+	})
+
+	for i := 0; i < fps; i++ {
+		s.Update(FPS(fps))
+	}
+
+	if n := s.Len(); n < 29 || n > 31 {
+		t.Logf("Want: %d", 30)
+		t.Logf("Got:  %d", n)
+		t.Fatal("particle count unexpected")
+	}
+}
+
+func TestParticleSystemLifetime(t *testing.T) {
+	s := NewParticleSystem(100, 1)
+	e := &Emitter{
+		Direction:   Vector{1, 0, 0},
+		MinSpeed:    5,
+		MaxSpeed:    5,
+		MinLifetime: 0.5,
+		MaxLifetime: 0.5,
+		Burst:       10,
+	}
+	s.AddEmitter(e)
+
+	s.Update(FPS(fps))
+	if s.Len() != 10 {
+		t.Fatalf("expected a burst of 10 particles, got %d", s.Len())
+	}
+	if e.Burst != 0 {
+		t.Fatal("expected burst to reset after emitting")
+	}
+
+	for i := 0; i < fps; i++ {
+		s.Update(FPS(fps))
+	}
+	if s.Len() != 0 {
+		t.Fatalf("expected all particles to have died, got %d", s.Len())
+	}
+
+	// Dead particles get recycled.
+	e.Burst = 100
+	s.Update(FPS(fps))
+	if s.Len() != 100 {
+		t.Fatalf("expected recycled particles to fill the pool, got %d", s.Len())
+	}
+}
+
+func TestParticleSystemCapacity(t *testing.T) {
+	s := NewParticleSystem(5, 1)
+	s.AddEmitter(&Emitter{MinLifetime: 1, MaxLifetime: 1, Burst: 50})
+	s.Update(FPS(fps))
+
+	if s.Len() != s.Cap() {
+		t.Fatalf("expected a full pool of %d, got %d", s.Cap(), s.Len())
+	}
+}
+
+func TestParticleSystemAllocations(t *testing.T) {
+	s := NewParticleSystem(200, 1)
+	s.AddEmitter(&Emitter{
+		Direction:    Vector{0, 1, 0},
+		Spread:       math.Pi / 4,
+		MinSpeed:     1,
+		MaxSpeed:     10,
+		MinLifetime:  0.1,
+		MaxLifetime:  1,
+		Rate:         500,
+		Acceleration: Gravity,
+	})
+
+	allocs := testing.AllocsPerRun(100, func() {
+		s.Update(FPS(fps))
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations, got %.0f", allocs)
+	}
+}
+
+func TestParticleSystemSpread(t *testing.T) {
+	for _, planar := range []bool{false, true} {
+		const spread = math.Pi / 8
+
+		s := NewParticleSystem(500, 1)
+		s.AddEmitter(&Emitter{
+			Direction:   Vector{1, 1, 0},
+			Spread:      spread,
+			MinSpeed:    1,
+			MaxSpeed:    1,
+			MinLifetime: 1,
+			MaxLifetime: 1,
+			Burst:       500,
+			Planar:      planar,
+		})
+		s.Update(FPS(fps))
+
+		for _, p := range s.Particles() {
+			v := p.Velocity()
+			angle := math.Acos((v.X + v.Y) / math.Sqrt2)
+			if angle > spread+1e-9 {
+				t.Fatalf("particle velocity %+v is outside the cone", v)
+			}
+			if planar && v.Z != 0 {
+				t.Fatalf("planar particle velocity %+v leaves the XY plane", v)
+			}
+		}
+	}
+}
+
+func TestParticleSystemDeterminism(t *testing.T) {
+	run := func() []Point {
+		s := NewParticleSystem(100, 42)
+		s.AddEmitter(&Emitter{
+			Direction:   Vector{0, 1, 0},
+			Spread:      math.Pi,
+			MinSpeed:    1,
+			MaxSpeed:    10,
+			MinLifetime: 1,
+			MaxLifetime: 2,
+			Rate:        60,
+		})
+		for i := 0; i < fps; i++ {
+			s.Update(FPS(fps))
+		}
+		var pts []Point
+		for _, p := range s.Particles() {
+			pts = append(pts, p.Position())
+		}
+		return pts
+	}
+
+	a, b := run(), run()
+	if len(a) != len(b) {
+		t.Fatal("particle counts differ between identical runs")
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal("particle positions differ between identical runs")
+		}
+	}
+}
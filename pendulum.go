@@ -0,0 +1,288 @@
+package harmonica
+
+// This file defines pendulums: a simple pendulum, optionally damped, and
+// a double pendulum, which is a pendulum hanging off the end of another and
+// moves chaotically. Both swing in the XY plane under the given gravity and
+// are integrated with the classic Runge-Kutta method.
+//
+// Example usage:
+//
+//    // Run once to initialize a pendulum hanging from (40, 2), pulled 45°
+//    // to the side.
+//    pendulum := NewPendulum(FPS(60), Point{40, 2, 0}, 10, math.Pi/4, TerminalGravity)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        bob := pendulum.Update()
+//    })
+//
+// Angles are in radians, measured from straight down. Positive angles swing
+// counter-clockwise with the Y axis pointing up; where the Y axis points down,
+// as with TerminalGravity, positive angles appear clockwise.
+//
+// For background on the equations of motion see:
+// https://en.wikipedia.org/wiki/Pendulum_(mechanics)
+// https://en.wikipedia.org/wiki/Double_pendulum
+
+import "math"
+
+// Pendulum is a simple pendulum: a weight, or bob, on a massless rod swinging
+// from a pivot.
+type Pendulum struct {
+	pivot     Point
+	length    float64
+	angle     float64
+	angVel    float64
+	damping   float64
+	gravity   Vector
+	deltaTime float64
+}
+
+// NewPendulum creates a new pendulum at rest. It accepts a time delta, the
+// pivot the pendulum swings from, the length of its rod, its initial angle and
+// the gravity acting on it, such as Gravity or TerminalGravity.
+func NewPendulum(deltaTime float64, pivot Point, length, angle float64, gravity Vector) *Pendulum {
+	return &Pendulum{
+		pivot:     pivot,
+		length:    math.Max(epsilon, length),
+		angle:     angle,
+		gravity:   gravity,
+		deltaTime: deltaTime,
+	}
+}
+
+// Update updates the angle and angular velocity of the pendulum and returns
+// the position of its bob.
+func (p *Pendulum) Update() Point {
+	g := planarGravity(p.gravity).Length()
+
+	// θ'' = -(g/L)·sin θ - c·θ'
+	accel := func(angle, angVel float64) float64 {
+		return -g/p.length*math.Sin(angle) - p.damping*angVel
+	}
+
+	var (
+		dt = p.deltaTime
+
+		k1a, k1v = p.angVel, accel(p.angle, p.angVel)
+		k2a, k2v = p.angVel + k1v*dt/2, accel(p.angle+k1a*dt/2, p.angVel+k1v*dt/2)
+		k3a, k3v = p.angVel + k2v*dt/2, accel(p.angle+k2a*dt/2, p.angVel+k2v*dt/2)
+		k4a, k4v = p.angVel + k3v*dt, accel(p.angle+k3a*dt, p.angVel+k3v*dt)
+	)
+
+	p.angle += (k1a + 2*k2a + 2*k3a + k4a) * dt / 6
+	p.angVel += (k1v + 2*k2v + 2*k3v + k4v) * dt / 6
+
+	return p.Bob()
+}
+
+// Bob returns the position of the pendulum's bob.
+func (p *Pendulum) Bob() Point {
+	return bobPosition(p.pivot, p.length, p.angle, p.gravity)
+}
+
+// Angle returns the angle of the pendulum from straight down, in radians.
+func (p *Pendulum) Angle() float64 {
+	return p.angle
+}
+
+// AngularVelocity returns the angular velocity of the pendulum in radians per
+// second.
+func (p *Pendulum) AngularVelocity() float64 {
+	return p.angVel
+}
+
+// SetAngle sets the angle of the pendulum from straight down, in radians.
+func (p *Pendulum) SetAngle(angle float64) {
+	p.angle = angle
+}
+
+// SetAngularVelocity sets the angular velocity of the pendulum in radians per
+// second. It's useful for giving a pendulum a push.
+func (p *Pendulum) SetAngularVelocity(angVel float64) {
+	p.angVel = angVel
+}
+
+// SetPivot moves the point the pendulum swings from.
+func (p *Pendulum) SetPivot(pivot Point) {
+	p.pivot = pivot
+}
+
+// SetDamping sets how quickly the pendulum loses energy, such as to friction
+// and air resistance. Zero, the default, swings forever. Higher values settle
+// faster.
+func (p *Pendulum) SetDamping(damping float64) {
+	p.damping = math.Max(0, damping)
+}
+
+// Period returns the time a full swing takes for small angles. Larger swings
+// take a little longer.
+func (p *Pendulum) Period() float64 {
+	g := planarGravity(p.gravity).Length()
+	if g < epsilon {
+		return math.Inf(1)
+	}
+	return 2 * math.Pi * math.Sqrt(p.length/g)
+}
+
+// DoublePendulum is a pendulum with a second pendulum hanging from its bob.
+// Its motion is chaotic: tiny changes in how it starts lead to wildly
+// different swings.
+type DoublePendulum struct {
+	pivot          Point
+	length1        float64
+	length2        float64
+	mass1, mass2   float64
+	angle1, angle2 float64
+	angVel1        float64
+	angVel2        float64
+	damping        float64
+	gravity        Vector
+	deltaTime      float64
+}
+
+// NewDoublePendulum creates a new double pendulum at rest. It accepts a time
+// delta, the pivot it swings from, the length and mass of each arm, the
+// initial angle of each arm and the gravity acting on it. The angle of the
+// second arm is measured from straight down, not relative to the first arm.
+func NewDoublePendulum(deltaTime float64, pivot Point, length1, mass1, length2, mass2, angle1, angle2 float64, gravity Vector) *DoublePendulum {
+	return &DoublePendulum{
+		pivot:     pivot,
+		length1:   math.Max(epsilon, length1),
+		length2:   math.Max(epsilon, length2),
+		mass1:     math.Max(epsilon, mass1),
+		mass2:     math.Max(epsilon, mass2),
+		angle1:    angle1,
+		angle2:    angle2,
+		gravity:   gravity,
+		deltaTime: deltaTime,
+	}
+}
+
+// Update updates the angles and angular velocities of the double pendulum and
+// returns the positions of both bobs.
+func (p *DoublePendulum) Update() (bob1, bob2 Point) {
+	var (
+		dt = p.deltaTime
+		s  = [4]float64{p.angle1, p.angle2, p.angVel1, p.angVel2}
+
+		k1 = p.derivative(s)
+		k2 = p.derivative(addScaled(s, k1, dt/2))
+		k3 = p.derivative(addScaled(s, k2, dt/2))
+		k4 = p.derivative(addScaled(s, k3, dt))
+	)
+
+	for i := range s {
+		s[i] += (k1[i] + 2*k2[i] + 2*k3[i] + k4[i]) * dt / 6
+	}
+	p.angle1, p.angle2, p.angVel1, p.angVel2 = s[0], s[1], s[2], s[3]
+
+	return p.Bobs()
+}
+
+// derivative returns the rate of change of the state (θ₁, θ₂, ω₁, ω₂).
+func (p *DoublePendulum) derivative(s [4]float64) [4]float64 {
+	var (
+		g          = planarGravity(p.gravity).Length()
+		m1, m2     = p.mass1, p.mass2
+		l1, l2     = p.length1, p.length2
+		a1, a2     = s[0], s[1]
+		w1, w2     = s[2], s[3]
+		delta      = a1 - a2
+		sinD, cosD = math.Sincos(delta)
+		den        = 2*m1 + m2 - m2*math.Cos(2*delta)
+	)
+
+	acc1 := (-g*(2*m1+m2)*math.Sin(a1) -
+		m2*g*math.Sin(a1-2*a2) -
+		2*sinD*m2*(w2*w2*l2+w1*w1*l1*cosD)) / (l1 * den)
+
+	acc2 := (2 * sinD * (w1*w1*l1*(m1+m2) +
+		g*(m1+m2)*math.Cos(a1) +
+		w2*w2*l2*m2*cosD)) / (l2 * den)
+
+	return [4]float64{w1, w2, acc1 - p.damping*w1, acc2 - p.damping*w2}
+}
+
+// addScaled returns s + k·h.
+func addScaled(s, k [4]float64, h float64) [4]float64 {
+	for i := range s {
+		s[i] += k[i] * h
+	}
+	return s
+}
+
+// Bobs returns the positions of both bobs.
+func (p *DoublePendulum) Bobs() (bob1, bob2 Point) {
+	bob1 = bobPosition(p.pivot, p.length1, p.angle1, p.gravity)
+	bob2 = bobPosition(bob1, p.length2, p.angle2, p.gravity)
+	return bob1, bob2
+}
+
+// Angles returns the angles of both arms from straight down, in radians.
+func (p *DoublePendulum) Angles() (angle1, angle2 float64) {
+	return p.angle1, p.angle2
+}
+
+// AngularVelocities returns the angular velocities of both arms in radians
+// per second.
+func (p *DoublePendulum) AngularVelocities() (angVel1, angVel2 float64) {
+	return p.angVel1, p.angVel2
+}
+
+// SetAngles sets the angles of both arms from straight down, in radians.
+func (p *DoublePendulum) SetAngles(angle1, angle2 float64) {
+	p.angle1, p.angle2 = angle1, angle2
+}
+
+// SetAngularVelocities sets the angular velocities of both arms in radians
+// per second.
+func (p *DoublePendulum) SetAngularVelocities(angVel1, angVel2 float64) {
+	p.angVel1, p.angVel2 = angVel1, angVel2
+}
+
+// SetPivot moves the point the double pendulum swings from.
+func (p *DoublePendulum) SetPivot(pivot Point) {
+	p.pivot = pivot
+}
+
+// SetDamping sets how quickly the double pendulum loses energy. Zero, the
+// default, swings forever.
+func (p *DoublePendulum) SetDamping(damping float64) {
+	p.damping = math.Max(0, damping)
+}
+
+// Energy returns the total kinetic and potential energy of the double
+// pendulum, with the pivot at zero potential energy. Without damping it stays
+// nearly constant.
+func (p *DoublePendulum) Energy() float64 {
+	var (
+		g      = planarGravity(p.gravity).Length()
+		m1, m2 = p.mass1, p.mass2
+		l1, l2 = p.length1, p.length2
+		w1, w2 = p.angVel1, p.angVel2
+
+		h1 = -l1 * math.Cos(p.angle1)
+		h2 = h1 - l2*math.Cos(p.angle2)
+
+		kinetic = 0.5*m1*l1*l1*w1*w1 +
+			0.5*m2*(l1*l1*w1*w1+l2*l2*w2*w2+2*l1*l2*w1*w2*math.Cos(p.angle1-p.angle2))
+	)
+	return kinetic + g*(m1*h1+m2*h2)
+}
+
+// planarGravity returns the part of the gravity vector in the XY plane, which
+// is the plane pendulums swing in.
+func planarGravity(gravity Vector) Vector {
+	return Vector{gravity.X, gravity.Y, 0}
+}
+
+// bobPosition returns the position of a pendulum bob at the given angle from
+// the direction of gravity.
+func bobPosition(pivot Point, length, angle float64, gravity Vector) Point {
+	down := planarGravity(gravity).Normalize()
+	if down == (Vector{}) {
+		down = Vector{0, -1, 0}
+	}
+	return pivot.Add(down.Rotate(angle).Scale(length))
+}
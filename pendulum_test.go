@@ -0,0 +1,75 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestPendulumPeriod(t *testing.T) {
+	p := NewPendulum(FPS(fps), Point{0, 0, 0}, 2, 0.05, Gravity)
+
+	// A small swing returns to its starting angle after one period.
+	steps := int(math.Round(p.Period() * fps))
+	for i := 0; i < steps; i++ {
+		p.Update()
+	}
+	if math.Abs(p.Angle()-0.05) > 0.002 {
+		t.Logf("Want: %.4f", 0.05)
+		t.Logf("Got:  %.4f", p.Angle())
+		t.Fatal("angle after one period unexpected")
+	}
+}
+
+func TestPendulumBob(t *testing.T) {
+	for _, tc := range []struct {
+		gravity Vector
+		want    Point
+	}{
+		{Gravity, Point{10, -5, 0}},
+		{TerminalGravity, Point{10, 5, 0}},
+	} {
+		p := NewPendulum(FPS(fps), Point{10, 0, 0}, 5, 0, tc.gravity)
+		if bob := p.Bob(); !equal(bob.X, tc.want.X) || !equal(bob.Y, tc.want.Y) {
+			t.Fatalf("bob at %+v, want %+v", bob, tc.want)
+		}
+	}
+
+	// A quarter turn counter-clockwise from down is to the right with Y up.
+	p := NewPendulum(FPS(fps), Point{0, 0, 0}, 5, math.Pi/2, Gravity)
+	if bob := p.Bob(); !equal(bob.X, 5) || !equal(bob.Y, 0) {
+		t.Fatalf("bob at %+v, want %+v", bob, Point{5, 0, 0})
+	}
+}
+
+func TestDampedPendulum(t *testing.T) {
+	p := NewPendulum(FPS(fps), Point{0, 0, 0}, 1, math.Pi/2, TerminalGravity)
+	p.SetDamping(2)
+
+	for i := 0; i < fps*10; i++ {
+		p.Update()
+	}
+	if math.Abs(p.Angle()) > 0.01 || math.Abs(p.AngularVelocity()) > 0.01 {
+		t.Fatalf("expected damped pendulum to settle, got angle %.3f", p.Angle())
+	}
+}
+
+func TestDoublePendulum(t *testing.T) {
+	p := NewDoublePendulum(FPS(fps), Point{0, 0, 0}, 1, 1, 1, 1, math.Pi/2, math.Pi, Gravity)
+	start := p.Energy()
+
+	for i := 0; i < fps*10; i++ {
+		bob1, bob2 := p.Update()
+		if d := bob1.Distance(Point{0, 0, 0}); !equal(d, 1) {
+			t.Fatalf("first arm stretched to %.3f", d)
+		}
+		if d := bob1.Distance(bob2); !equal(d, 1) {
+			t.Fatalf("second arm stretched to %.3f", d)
+		}
+	}
+
+	if e := p.Energy(); math.Abs(e-start) > 0.01*math.Abs(start)+0.01 {
+		t.Fatalf("energy drifted from %.4f to %.4f", start, e)
+	}
+}
@@ -21,12 +21,16 @@ package harmonica
 // https://en.wikipedia.org/wiki/Projectile_motion
 
 // Projectile is the representation of a projectile that has a position on
-// a plane, an acceleration, and velocity.
+// a plane, an acceleration, velocity, and mass.
 type Projectile struct {
 	pos       Point
 	vel       Vector
 	acc       Vector
+	mass      float64
+	force     Vector // accumulated for the next step
 	deltaTime float64
+	forces    []Force
+	time      float64
 }
 
 // Point represents a point containing the X, Y, Z coordinates of the point on
@@ -60,12 +64,13 @@ var TerminalGravity = Vector{0, 9.81, 0}
 
 // NewProjectile creates a new projectile. It accepts a frame rate and initial
 // values for position, velocity, and acceleration. It returns a new
-// projectile.
+// projectile with a mass of 1.
 func NewProjectile(deltaTime float64, initialPosition Point, initialVelocity, initalAcceleration Vector) *Projectile {
 	return &Projectile{
 		pos:       initialPosition,
 		vel:       initialVelocity,
 		acc:       initalAcceleration,
+		mass:      1,
 		deltaTime: deltaTime,
 	}
 }
@@ -73,17 +78,76 @@ func NewProjectile(deltaTime float64, initialPosition Point, initialVelocity, in
 // Update updates the position and velocity values for the given projectile.
 // Call this after calling NewProjectile to update values.
 func (p *Projectile) Update() Point {
-	p.pos.X += (p.vel.X * p.deltaTime)
-	p.pos.Y += (p.vel.Y * p.deltaTime)
-	p.pos.Z += (p.vel.Z * p.deltaTime)
+	return p.step(p.deltaTime, nil)
+}
+
+// UpdateDelta is like Update, but advances by the given time delta instead of
+// the one the projectile was initialized with, such as one from a TimeSource.
+func (p *Projectile) UpdateDelta(deltaTime float64) Point {
+	if deltaTime < 0 {
+		deltaTime = 0
+	}
+	return p.step(deltaTime, nil)
+}
+
+// step advances the projectile by the given time delta. Any extra forces are
+// applied in addition to the projectile's own.
+func (p *Projectile) step(deltaTime float64, extra []Force) Point {
+	acc := p.acc.Add(p.force.Scale(p.inverseMass()))
+	p.force = Vector{}
+	for _, forces := range [2][]Force{p.forces, extra} {
+		for _, f := range forces {
+			acc = acc.Add(f.Acceleration(p.pos, p.vel, p.time))
+		}
+	}
+
+	p.pos.X += (p.vel.X * deltaTime)
+	p.pos.Y += (p.vel.Y * deltaTime)
+	p.pos.Z += (p.vel.Z * deltaTime)
+
+	p.vel.X += (acc.X * deltaTime)
+	p.vel.Y += (acc.Y * deltaTime)
+	p.vel.Z += (acc.Z * deltaTime)
 
-	p.vel.X += (p.acc.X * p.deltaTime)
-	p.vel.Y += (p.acc.Y * p.deltaTime)
-	p.vel.Z += (p.acc.Z * p.deltaTime)
+	p.time += deltaTime
 
 	return p.pos
 }
 
+// ApplyForce applies a force to the projectile. Forces accumulate until the
+// next update, where they accelerate the projectile in proportion to its mass
+// and are then cleared. To apply a force continuously, apply it before every
+// update.
+func (p *Projectile) ApplyForce(force Vector) {
+	p.force = p.force.Add(force)
+}
+
+// ApplyImpulse applies an impulse to the projectile, such as a hit, changing
+// its velocity immediately in proportion to its mass.
+func (p *Projectile) ApplyImpulse(impulse Vector) {
+	p.vel = p.vel.Add(impulse.Scale(p.inverseMass()))
+}
+
+// inverseMass returns the reciprocal of the projectile's mass. A projectile
+// without a mass, such as the zero value, is treated as having a mass of 1.
+func (p *Projectile) inverseMass() float64 {
+	if p.mass <= 0 {
+		return 1
+	}
+	return 1 / p.mass
+}
+
+// AddForce layers a force field on the projectile. It's applied on every
+// update in addition to the projectile's acceleration.
+func (p *Projectile) AddForce(f Force) {
+	p.forces = append(p.forces, f)
+}
+
+// ClearForces removes all force fields from the projectile.
+func (p *Projectile) ClearForces() {
+	p.forces = nil
+}
+
 // Position returns the position of the projectile.
 func (p *Projectile) Position() Point {
 	return p.pos
@@ -98,3 +162,36 @@ func (p *Projectile) Velocity() Vector {
 func (p *Projectile) Acceleration() Vector {
 	return p.acc
 }
+
+// Mass returns the mass of the projectile.
+func (p *Projectile) Mass() float64 {
+	if p.mass <= 0 {
+		return 1
+	}
+	return p.mass
+}
+
+// SetPosition moves the projectile to the given position.
+func (p *Projectile) SetPosition(pos Point) {
+	p.pos = pos
+}
+
+// SetVelocity sets the velocity of the projectile.
+func (p *Projectile) SetVelocity(vel Vector) {
+	p.vel = vel
+}
+
+// SetAcceleration sets the constant acceleration of the projectile, such as
+// gravity.
+func (p *Projectile) SetAcceleration(acc Vector) {
+	p.acc = acc
+}
+
+// SetMass sets the mass of the projectile, which determines how it responds to
+// forces and impulses. It doesn't affect the constant acceleration. Masses
+// must be positive; other values are ignored.
+func (p *Projectile) SetMass(mass float64) {
+	if mass > 0 {
+		p.mass = mass
+	}
+}
@@ -102,3 +102,63 @@ func TestUpdateGravity(t *testing.T) {
 		}
 	}
 }
+
+func TestSetters(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{1, 0, 0}, Gravity)
+
+	projectile.SetPosition(Point{10, 10, 0})
+	projectile.SetVelocity(Vector{0, 5, 0})
+	projectile.SetAcceleration(Vector{0, 0, 0})
+
+	for i := 0; i < fps; i++ {
+		projectile.Update()
+	}
+
+	pos := projectile.Position()
+	if !equal(pos.X, 10) || !equal(pos.Y, 15) {
+		t.Logf("Want: (%.2f, %.2f)", 10.0, 15.0)
+		t.Logf("Got:  (%.2f, %.2f)", pos.X, pos.Y)
+		t.Fatal("coordinate unexpected")
+	}
+
+	projectile.SetMass(-1)
+	if projectile.Mass() != 1 {
+		t.Fatal("expected non-positive mass to be ignored")
+	}
+}
+
+func TestApplyForce(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{0, 0, 0}, Vector{0, 0, 0})
+	projectile.SetMass(2)
+
+	// A constant force of 4 on a mass of 2 accelerates it by 2 units/s².
+	for i := 0; i < fps; i++ {
+		projectile.ApplyForce(Vector{4, 0, 0})
+		projectile.Update()
+	}
+	if vel := projectile.Velocity(); !equal(vel.X, 2) {
+		t.Logf("Want: %.2f", 2.0)
+		t.Logf("Got:  %.2f", vel.X)
+		t.Fatal("velocity unexpected")
+	}
+
+	// Forces are cleared after each update.
+	projectile.Update()
+	if vel := projectile.Velocity(); !equal(vel.X, 2) {
+		t.Logf("Want: %.2f", 2.0)
+		t.Logf("Got:  %.2f", vel.X)
+		t.Fatal("force persisted past update")
+	}
+}
+
+func TestApplyImpulse(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{1, 0, 0}, Vector{0, 0, 0})
+	projectile.SetMass(4)
+	projectile.ApplyImpulse(Vector{0, 8, 0})
+
+	if vel := projectile.Velocity(); !equal(vel.X, 1) || !equal(vel.Y, 2) {
+		t.Logf("Want: (%.2f, %.2f)", 1.0, 2.0)
+		t.Logf("Got:  (%.2f, %.2f)", vel.X, vel.Y)
+		t.Fatal("velocity unexpected")
+	}
+}
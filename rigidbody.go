@@ -0,0 +1,186 @@
+package harmonica
+
+// This file defines a 2D rigid body: a projectile which also rotates. Forces
+// applied away from the body's center of mass make it spin.
+//
+// Example usage:
+//
+//    // Run once to initialize a 4x2 card.
+//    card := NewRigidBody(
+//        FPS(60),
+//        Point{10, 0, 0},
+//        Vector{5, 0, 0},
+//        TerminalGravity,
+//        1.0,
+//        BoxInertia(1.0, 4, 2),
+//    )
+//
+//    // Flick its corner.
+//    card.ApplyImpulseAtPoint(Vector{0, -3, 0}, Point{12, 1, 0})
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        pos := card.Update()
+//        angle := card.Angle()
+//    })
+//
+// Rotation happens in the XY plane. Positive angles are counter-clockwise
+// with the Y axis pointing up; where the Y axis points down, as with
+// TerminalGravity, positive angles appear clockwise.
+
+// RigidBody is a projectile with an orientation, angular velocity and moment
+// of inertia. Its position is its center of mass.
+//
+// Only the body's own Update, UpdateDelta and UpdateSwept rotate it. Anything
+// that takes a *Projectile, such as Group, Animator and BakeProjectile, is
+// linear-only: given the embedded Projectile, it moves the body but leaves its
+// angle and angular velocity untouched. Colliders are the exception, since
+// collisions don't affect rotation; they're safe to build from the embedded
+// Projectile as long as the body itself is updated with Update.
+type RigidBody struct {
+	Projectile
+	angle   float64
+	angVel  float64
+	inertia float64
+	torque  float64 // accumulated for the next step
+}
+
+// NewRigidBody creates a new rigid body. In addition to the values accepted by
+// NewProjectile it takes the body's mass and its moment of inertia around its
+// center of mass, which BoxInertia and DiskInertia can help compute.
+func NewRigidBody(deltaTime float64, initialPosition Point, initialVelocity, initialAcceleration Vector, mass, inertia float64) *RigidBody {
+	b := &RigidBody{
+		Projectile: *NewProjectile(deltaTime, initialPosition, initialVelocity, initialAcceleration),
+		inertia:    1,
+	}
+	b.SetMass(mass)
+	b.SetInertia(inertia)
+	return b
+}
+
+// BoxInertia returns the moment of inertia of a solid rectangle of the given
+// mass and size rotating around its center.
+func BoxInertia(mass, width, height float64) float64 {
+	return mass * (width*width + height*height) / 12
+}
+
+// DiskInertia returns the moment of inertia of a solid disk of the given mass
+// and radius rotating around its center.
+func DiskInertia(mass, radius float64) float64 {
+	return mass * radius * radius / 2
+}
+
+// Update updates the position, velocity, orientation and angular velocity of
+// the body. Call this after calling NewRigidBody to update values.
+func (b *RigidBody) Update() Point {
+	return b.step(b.deltaTime)
+}
+
+// UpdateDelta is like Update, but advances by the given time delta instead of
+// the one the body was initialized with, such as one from a TimeSource.
+func (b *RigidBody) UpdateDelta(deltaTime float64) Point {
+	if deltaTime < 0 {
+		deltaTime = 0
+	}
+	return b.step(deltaTime)
+}
+
+// step advances the body, including its rotation, by the given time delta.
+func (b *RigidBody) step(deltaTime float64) Point {
+	pos := b.Projectile.step(deltaTime, nil)
+
+	b.angle += b.angVel * deltaTime
+	b.angVel += b.torque / b.inertia * deltaTime
+	b.torque = 0
+
+	return pos
+}
+
+// UpdateSwept updates the body like Update, including its rotation, and
+// sweeps it as a sphere of the given radius as Projectile.UpdateSwept does.
+func (b *RigidBody) UpdateSwept(radius float64, obstacles ...Obstacle) (Point, Hit, bool) {
+	return b.updateSwept(radius, obstacles, b.Update)
+}
+
+// Angle returns the orientation of the body in radians.
+func (b *RigidBody) Angle() float64 {
+	return b.angle
+}
+
+// AngularVelocity returns the angular velocity of the body in radians per
+// second.
+func (b *RigidBody) AngularVelocity() float64 {
+	return b.angVel
+}
+
+// Inertia returns the moment of inertia of the body.
+func (b *RigidBody) Inertia() float64 {
+	return b.inertia
+}
+
+// SetAngle sets the orientation of the body in radians.
+func (b *RigidBody) SetAngle(angle float64) {
+	b.angle = angle
+}
+
+// SetAngularVelocity sets the angular velocity of the body in radians per
+// second.
+func (b *RigidBody) SetAngularVelocity(angVel float64) {
+	b.angVel = angVel
+}
+
+// SetInertia sets the moment of inertia of the body. Moments of inertia must
+// be positive; other values are ignored.
+func (b *RigidBody) SetInertia(inertia float64) {
+	if inertia > 0 {
+		b.inertia = inertia
+	}
+}
+
+// ApplyTorque applies a torque to the body. Like forces, torques accumulate
+// until the next update, where they're applied and then cleared.
+func (b *RigidBody) ApplyTorque(torque float64) {
+	b.torque += torque
+}
+
+// ApplyAngularImpulse applies an angular impulse to the body, changing its
+// angular velocity immediately.
+func (b *RigidBody) ApplyAngularImpulse(impulse float64) {
+	b.angVel += impulse / b.inertia
+}
+
+// ApplyForceAtPoint applies a force at a point in world space. The force
+// accelerates the body as ApplyForce does and, if the point is away from the
+// center of mass, also produces a torque that makes it spin.
+func (b *RigidBody) ApplyForceAtPoint(force Vector, point Point) {
+	b.ApplyForce(force)
+	b.ApplyTorque(point.Sub(b.pos).Cross(force).Z)
+}
+
+// ApplyImpulseAtPoint applies an impulse at a point in world space, changing
+// both the linear and angular velocity of the body immediately.
+func (b *RigidBody) ApplyImpulseAtPoint(impulse Vector, point Point) {
+	b.ApplyImpulse(impulse)
+	b.ApplyAngularImpulse(point.Sub(b.pos).Cross(impulse).Z)
+}
+
+// VelocityAt returns the velocity of a point in world space attached to the
+// body, which combines the body's linear velocity with its spin.
+func (b *RigidBody) VelocityAt(point Point) Vector {
+	r := point.Sub(b.pos)
+	return b.vel.Add(Vector{0, 0, b.angVel}.Cross(r))
+}
+
+// ToWorld converts a point relative to the body's center and orientation into
+// world space. It's useful for finding where the corners of a shape are when
+// rendering.
+func (b *RigidBody) ToWorld(local Point) Point {
+	return b.pos.Add(local.Vector().Rotate(b.angle))
+}
+
+// Clone returns an independent copy of the rigid body.
+func (b *RigidBody) Clone() *RigidBody {
+	c := *b
+	c.Projectile = *b.Projectile.Clone()
+	return &c
+}
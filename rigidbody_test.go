@@ -0,0 +1,118 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestRigidBodyForceAtCenter(t *testing.T) {
+	body := NewRigidBody(FPS(fps), Point{0, 0, 0}, Vector{}, Vector{}, 2, BoxInertia(2, 4, 2))
+
+	for i := 0; i < fps; i++ {
+		body.ApplyForceAtPoint(Vector{4, 0, 0}, body.Position())
+		body.Update()
+	}
+
+	if vel := body.Velocity(); !equal(vel.X, 2) {
+		t.Logf("Want: %.2f", 2.0)
+		t.Logf("Got:  %.2f", vel.X)
+		t.Fatal("velocity unexpected")
+	}
+	if body.AngularVelocity() != 0 {
+		t.Fatal("force through the center of mass caused rotation")
+	}
+}
+
+func TestRigidBodyImpulseAtPoint(t *testing.T) {
+	const mass = 1.0
+	inertia := DiskInertia(mass, 2)
+	body := NewRigidBody(FPS(fps), Point{0, 0, 0}, Vector{}, Vector{}, mass, inertia)
+
+	// Push the right edge upwards: a counter-clockwise spin.
+	body.ApplyImpulseAtPoint(Vector{0, 3, 0}, Point{2, 0, 0})
+
+	if vel := body.Velocity(); !equal(vel.Y, 3) {
+		t.Logf("Want: %.2f", 3.0)
+		t.Logf("Got:  %.2f", vel.Y)
+		t.Fatal("velocity unexpected")
+	}
+	if want := 2 * 3 / inertia; !equal(body.AngularVelocity(), want) {
+		t.Logf("Want: %.2f", want)
+		t.Logf("Got:  %.2f", body.AngularVelocity())
+		t.Fatal("angular velocity unexpected")
+	}
+
+	// The right edge moves faster than the center.
+	if v := body.VelocityAt(Point{2, 0, 0}); !equal(v.Y, 3+2*body.AngularVelocity()) {
+		t.Fatalf("edge velocity unexpected: %+v", v)
+	}
+
+	for i := 0; i < fps; i++ {
+		body.Update()
+	}
+	if !equal(body.Angle(), body.AngularVelocity()) {
+		t.Logf("Want: %.2f", body.AngularVelocity())
+		t.Logf("Got:  %.2f", body.Angle())
+		t.Fatal("angle unexpected")
+	}
+}
+
+func TestRigidBodyToWorld(t *testing.T) {
+	body := NewRigidBody(FPS(fps), Point{10, 5, 0}, Vector{}, Vector{}, 1, 1)
+	body.SetAngle(math.Pi / 2)
+
+	p := body.ToWorld(Point{2, 0, 0})
+	if !equal(p.X, 10) || !equal(p.Y, 7) {
+		t.Logf("Want: (%.2f, %.2f)", 10.0, 7.0)
+		t.Logf("Got:  (%.2f, %.2f)", p.X, p.Y)
+		t.Fatal("world coordinate unexpected")
+	}
+}
+
+func TestRigidBodyUpdateDelta(t *testing.T) {
+	ts := NewTimeSource(FPS(fps))
+	ts.SetScale(0.5)
+	body := NewRigidBody(FPS(fps), Point{0, 0, 0}, Vector{1, 0, 0}, Vector{}, 1, 1)
+	body.SetAngularVelocity(1)
+
+	// Two seconds at half speed is one second of motion.
+	for i := 0; i < fps*2; i++ {
+		ts.Advance()
+		body.ApplyTorque(1)
+		body.UpdateDelta(ts.DeltaTime())
+	}
+
+	if pos := body.Position(); !equal(pos.X, 1) {
+		t.Logf("Want: %.2f", 1.0)
+		t.Logf("Got:  %.2f", pos.X)
+		t.Fatal("position unexpected")
+	}
+	if !equal(body.AngularVelocity(), 2) {
+		t.Logf("Want: %.2f", 2.0)
+		t.Logf("Got:  %.2f", body.AngularVelocity())
+		t.Fatal("angular velocity unexpected")
+	}
+	if !equal(body.Angle(), 1.5) {
+		t.Logf("Want: %.2f", 1.5)
+		t.Logf("Got:  %.2f", body.Angle())
+		t.Fatal("angle unexpected")
+	}
+}
+
+func TestRigidBodyUpdateSwept(t *testing.T) {
+	body := NewRigidBody(FPS(fps), Point{0, 0, 0}, Vector{0, -1, 0}, Vector{}, 1, 1)
+	body.SetAngularVelocity(1)
+	floor := Plane{Point: Point{0, -10, 0}, Normal: Vector{0, 1, 0}}
+
+	for i := 0; i < fps; i++ {
+		body.UpdateSwept(0.5, floor)
+	}
+
+	if !equal(body.Angle(), 1) {
+		t.Logf("Want: %.2f", 1.0)
+		t.Logf("Got:  %.2f", body.Angle())
+		t.Fatal("swept update didn't rotate the body")
+	}
+}
@@ -0,0 +1,135 @@
+package harmonica
+
+// This file defines trauma-based shake, for rattling a camera or the whole
+// screen when something goes wrong. Events add trauma, trauma decays over
+// time, and the shake's intensity is the square of the trauma, so small
+// knocks are subtle and big hits are violent. The motion itself comes from
+// smooth noise rather than random offsets, so it jolts instead of jitters.
+//
+// Example usage:
+//
+//    // Run once to initialize a shake of up to 3 cells sideways and 1 cell
+//    // vertically that recovers from full trauma in one second.
+//    shake := NewShake(FPS(60), Vector{3, 1, 0}, 0, 15, 1, 42)
+//
+//    // On error.
+//    shake.AddTrauma(0.5)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        shake.Update()
+//        offset := shake.Offset()
+//    })
+//
+// For background on the noise see:
+// https://en.wikipedia.org/wiki/Perlin_noise
+
+import "math"
+
+// Noise coordinates for each channel of the shake. They're far enough apart to
+// be independent and off the integer lattice, where the noise is always zero.
+const (
+	shakeChannelX     = 0.5
+	shakeChannelY     = 37.25
+	shakeChannelZ     = 71.75
+	shakeChannelAngle = 113.125
+	shakeNoiseDepth   = 0.375
+)
+
+// Shake produces smooth, pseudorandom offsets and rotation whose strength
+// depends on accumulated trauma.
+type Shake struct {
+	trauma    float64
+	decay     float64
+	frequency float64
+	maxOffset Vector
+	maxAngle  float64
+	time      float64
+	deltaTime float64
+	noise     *perlin
+}
+
+// NewShake creates a new shake with no trauma. It accepts a time delta, the
+// maximum offset along each axis, the maximum rotation in radians, the
+// frequency of the shake, the rate at which trauma decays per second and a
+// seed.
+//
+// Set axes you don't need to zero: Vector{2, 0, 0} shakes only horizontally,
+// for example. The frequency is roughly how many jolts happen per second.
+// Shakes with the same seed move identically.
+func NewShake(deltaTime float64, maxOffset Vector, maxAngle, frequency, decay float64, seed int64) *Shake {
+	return &Shake{
+		decay:     math.Max(0, decay),
+		frequency: frequency,
+		maxOffset: maxOffset,
+		maxAngle:  maxAngle,
+		deltaTime: deltaTime,
+		noise:     newPerlin(seed),
+	}
+}
+
+// AddTrauma adds trauma, which is capped at 1. Negative values calm the shake
+// down.
+func (s *Shake) AddTrauma(amount float64) {
+	s.SetTrauma(s.trauma + amount)
+}
+
+// SetTrauma sets the trauma, from 0 for no shake to 1 for the most violent.
+func (s *Shake) SetTrauma(trauma float64) {
+	s.trauma = math.Max(0, math.Min(1, trauma))
+}
+
+// Trauma returns the current trauma, from 0 to 1.
+func (s *Shake) Trauma() float64 {
+	return s.trauma
+}
+
+// Intensity returns the strength of the shake, which is the square of the
+// trauma.
+func (s *Shake) Intensity() float64 {
+	return s.trauma * s.trauma
+}
+
+// Update advances the shake by one time step and lets trauma decay.
+func (s *Shake) Update() {
+	s.time += s.deltaTime
+	s.trauma = math.Max(0, s.trauma-s.decay*s.deltaTime)
+
+	// Once calm, restart the noise so precision doesn't degrade over a long
+	// running program.
+	if s.trauma == 0 {
+		s.time = 0
+	}
+}
+
+// Offset returns the current translational offset. Each component is within
+// the maximum offset for its axis, scaled by the intensity.
+func (s *Shake) Offset() Vector {
+	i := s.Intensity()
+	if i == 0 {
+		return Vector{}
+	}
+	return Vector{
+		X: s.maxOffset.X * i * s.sample(shakeChannelX),
+		Y: s.maxOffset.Y * i * s.sample(shakeChannelY),
+		Z: s.maxOffset.Z * i * s.sample(shakeChannelZ),
+	}
+}
+
+// Angle returns the current rotational offset in radians. It's within the
+// maximum rotation, scaled by the intensity.
+func (s *Shake) Angle() float64 {
+	return s.maxAngle * s.Intensity() * s.sample(shakeChannelAngle)
+}
+
+// Magnitude returns the length of the current offset.
+func (s *Shake) Magnitude() float64 {
+	return s.Offset().Length()
+}
+
+// sample returns noise for the given channel at the current time, in the
+// range [-1, 1].
+func (s *Shake) sample(channel float64) float64 {
+	n := s.noise.noise3(s.time*s.frequency, channel, shakeNoiseDepth)
+	return math.Max(-1, math.Min(1, n))
+}
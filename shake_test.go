@@ -0,0 +1,83 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestShakeDecay(t *testing.T) {
+	s := NewShake(FPS(fps), Vector{3, 1, 0}, 0.1, 15, 2, 42)
+	if s.Offset() != (Vector{}) || s.Angle() != 0 {
+		t.Fatal("expected no shake without trauma")
+	}
+
+	s.AddTrauma(0.6)
+	s.AddTrauma(0.6)
+	if s.Trauma() != 1 {
+		t.Fatalf("expected trauma to be capped at 1, got %.2f", s.Trauma())
+	}
+
+	// Trauma decays at 2 per second, so it's gone after half a second.
+	var moved bool
+	for i := 0; i < fps/2; i++ {
+		s.Update()
+		off := s.Offset()
+		if math.Abs(off.X) > 3*s.Intensity() || math.Abs(off.Y) > s.Intensity() || off.Z != 0 {
+			t.Fatalf("offset %+v out of range for intensity %.2f", off, s.Intensity())
+		}
+		if math.Abs(s.Angle()) > 0.1*s.Intensity() {
+			t.Fatalf("angle %.3f out of range for intensity %.2f", s.Angle(), s.Intensity())
+		}
+		moved = moved || off != (Vector{})
+	}
+	if !moved {
+		t.Fatal("expected shake to move")
+	}
+
+	s.Update()
+	if s.Trauma() != 0 || s.Offset() != (Vector{}) {
+		t.Fatalf("expected shake to settle, got trauma %.2f", s.Trauma())
+	}
+}
+
+func TestShakeDeterministic(t *testing.T) {
+	a := NewShake(FPS(fps), Vector{3, 1, 1}, 0.1, 15, 0.5, 7)
+	b := NewShake(FPS(fps), Vector{3, 1, 1}, 0.1, 15, 0.5, 7)
+	c := NewShake(FPS(fps), Vector{3, 1, 1}, 0.1, 15, 0.5, 8)
+	a.AddTrauma(1)
+	b.AddTrauma(1)
+	c.AddTrauma(1)
+
+	var differs bool
+	for i := 0; i < fps; i++ {
+		a.Update()
+		b.Update()
+		c.Update()
+		if a.Offset() != b.Offset() || a.Angle() != b.Angle() {
+			t.Fatalf("shakes with the same seed diverged on frame %d", i)
+		}
+		differs = differs || a.Offset() != c.Offset()
+	}
+	if !differs {
+		t.Fatal("expected shakes with different seeds to differ")
+	}
+}
+
+func TestShakeSmooth(t *testing.T) {
+	s := NewShake(FPS(fps), Vector{1, 0, 0}, 0, 10, 0, 3)
+	s.SetTrauma(1)
+
+	// Coherent noise moves a little from frame to frame rather than jumping
+	// anywhere in its range.
+	prev := s.Offset().X
+	for i := 0; i < fps*2; i++ {
+		s.Update()
+		x := s.Offset().X
+		if math.Abs(x-prev) > 0.5 {
+			t.Fatalf("shake jumped from %.2f to %.2f", prev, x)
+		}
+		prev = x
+	}
+}
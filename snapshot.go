@@ -0,0 +1,242 @@
+package harmonica
+
+// This file defines snapshots of simulation state. Snapshots are plain values
+// which can be kept around to roll a simulation back, used to fork
+// a simulation to preview what might happen, or encoded and sent elsewhere.
+//
+// Example usage:
+//
+//    // Save the state.
+//    snap := projectile.Snapshot()
+//
+//    // Preview the next second on a copy.
+//    preview := projectile.Clone()
+//    for i := 0; i < 60; i++ {
+//        preview.Update()
+//    }
+//
+//    // Roll back.
+//    projectile.Restore(snap)
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// snapshotVersion is the version of the binary snapshot encoding.
+const snapshotVersion = 1
+
+// ErrInvalidSnapshot is returned when decoding malformed binary snapshot data.
+var ErrInvalidSnapshot = errors.New("harmonica: invalid snapshot data")
+
+// ProjectileSnapshot is the state of a Projectile at a moment in time.
+//
+// Force fields added with AddForce aren't part of the state; they may be
+// arbitrary code and can't be encoded. Restoring a snapshot leaves the
+// projectile's force fields as they are.
+type ProjectileSnapshot struct {
+	Position     Point   `json:"position"`
+	Velocity     Vector  `json:"velocity"`
+	Acceleration Vector  `json:"acceleration"`
+	Mass         float64 `json:"mass"`
+
+	// Force is the force applied with ApplyForce which is pending for the
+	// next update.
+	Force Vector `json:"force"`
+
+	DeltaTime float64 `json:"deltaTime"`
+
+	// Time is how long, in seconds, the projectile has been simulated for.
+	Time float64 `json:"time"`
+}
+
+// Snapshot returns the current state of the projectile.
+func (p *Projectile) Snapshot() ProjectileSnapshot {
+	return ProjectileSnapshot{
+		Position:     p.pos,
+		Velocity:     p.vel,
+		Acceleration: p.acc,
+		Mass:         p.Mass(),
+		Force:        p.force,
+		DeltaTime:    p.deltaTime,
+		Time:         p.time,
+	}
+}
+
+// Restore sets the projectile's state to a snapshot.
+func (p *Projectile) Restore(s ProjectileSnapshot) {
+	p.pos = s.Position
+	p.vel = s.Velocity
+	p.acc = s.Acceleration
+	p.mass = s.Mass
+	p.force = s.Force
+	p.deltaTime = s.DeltaTime
+	p.time = s.Time
+}
+
+// Clone returns an independent copy of the projectile, including its force
+// fields. Updating the copy doesn't affect the original, and vice versa.
+func (p *Projectile) Clone() *Projectile {
+	c := *p
+	c.forces = append([]Force(nil), p.forces...)
+	return &c
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s ProjectileSnapshot) MarshalBinary() ([]byte, error) {
+	return encodeFloats(s.floats()...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *ProjectileSnapshot) UnmarshalBinary(data []byte) error {
+	f, err := decodeFloats(data, projectileSnapshotFloats)
+	if err != nil {
+		return err
+	}
+	s.setFloats(f)
+	return nil
+}
+
+// projectileSnapshotFloats is the number of values in an encoded
+// ProjectileSnapshot.
+const projectileSnapshotFloats = 15
+
+// floats returns the values of the snapshot in encoding order.
+func (s ProjectileSnapshot) floats() []float64 {
+	return []float64{
+		s.Position.X, s.Position.Y, s.Position.Z,
+		s.Velocity.X, s.Velocity.Y, s.Velocity.Z,
+		s.Acceleration.X, s.Acceleration.Y, s.Acceleration.Z,
+		s.Mass,
+		s.Force.X, s.Force.Y, s.Force.Z,
+		s.DeltaTime,
+		s.Time,
+	}
+}
+
+// setFloats sets the snapshot from values in encoding order.
+func (s *ProjectileSnapshot) setFloats(f []float64) {
+	*s = ProjectileSnapshot{
+		Position:     Point{f[0], f[1], f[2]},
+		Velocity:     Vector{f[3], f[4], f[5]},
+		Acceleration: Vector{f[6], f[7], f[8]},
+		Mass:         f[9],
+		Force:        Vector{f[10], f[11], f[12]},
+		DeltaTime:    f[13],
+		Time:         f[14],
+	}
+}
+
+// RigidBodySnapshot is the state of a RigidBody at a moment in time: the state
+// of its projectile, plus its rotation.
+type RigidBodySnapshot struct {
+	ProjectileSnapshot
+
+	Angle           float64 `json:"angle"`
+	AngularVelocity float64 `json:"angularVelocity"`
+	Inertia         float64 `json:"inertia"`
+
+	// Torque is the torque applied with ApplyTorque which is pending for the
+	// next update.
+	Torque float64 `json:"torque"`
+}
+
+// Snapshot returns the current state of the rigid body.
+func (b *RigidBody) Snapshot() RigidBodySnapshot {
+	return RigidBodySnapshot{
+		ProjectileSnapshot: b.Projectile.Snapshot(),
+		Angle:              b.angle,
+		AngularVelocity:    b.angVel,
+		Inertia:            b.inertia,
+		Torque:             b.torque,
+	}
+}
+
+// Restore sets the rigid body's state to a snapshot.
+func (b *RigidBody) Restore(s RigidBodySnapshot) {
+	b.Projectile.Restore(s.ProjectileSnapshot)
+	b.angle = s.Angle
+	b.angVel = s.AngularVelocity
+	b.inertia = s.Inertia
+	b.torque = s.Torque
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s RigidBodySnapshot) MarshalBinary() ([]byte, error) {
+	return encodeFloats(append(s.ProjectileSnapshot.floats(),
+		s.Angle,
+		s.AngularVelocity,
+		s.Inertia,
+		s.Torque,
+	)...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *RigidBodySnapshot) UnmarshalBinary(data []byte) error {
+	f, err := decodeFloats(data, projectileSnapshotFloats+4)
+	if err != nil {
+		return err
+	}
+	*s = RigidBodySnapshot{
+		Angle:           f[projectileSnapshotFloats],
+		AngularVelocity: f[projectileSnapshotFloats+1],
+		Inertia:         f[projectileSnapshotFloats+2],
+		Torque:          f[projectileSnapshotFloats+3],
+	}
+	s.ProjectileSnapshot.setFloats(f)
+	return nil
+}
+
+// SpringState is the position and velocity of a value animated by a Spring.
+// The Spring itself holds no state, so this, along with the Spring's
+// parameters, is all that's needed to save and restore a spring animation.
+type SpringState struct {
+	Position float64 `json:"position"`
+	Velocity float64 `json:"velocity"`
+}
+
+// Update returns the state after one step of the spring towards the given
+// target. It's a convenience wrapper around Spring.Update.
+func (st SpringState) Update(s Spring, equilibriumPos float64) SpringState {
+	st.Position, st.Velocity = s.Update(st.Position, st.Velocity, equilibriumPos)
+	return st
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (st SpringState) MarshalBinary() ([]byte, error) {
+	return encodeFloats(st.Position, st.Velocity), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (st *SpringState) UnmarshalBinary(data []byte) error {
+	f, err := decodeFloats(data, 2)
+	if err != nil {
+		return err
+	}
+	*st = SpringState{Position: f[0], Velocity: f[1]}
+	return nil
+}
+
+// encodeFloats encodes a version byte followed by the given values as little
+// endian IEEE 754 doubles.
+func encodeFloats(fs ...float64) []byte {
+	b := make([]byte, 1+8*len(fs))
+	b[0] = snapshotVersion
+	for i, f := range fs {
+		binary.LittleEndian.PutUint64(b[1+8*i:], math.Float64bits(f))
+	}
+	return b
+}
+
+// decodeFloats decodes n values encoded with encodeFloats.
+func decodeFloats(b []byte, n int) ([]float64, error) {
+	if len(b) != 1+8*n || b[0] != snapshotVersion {
+		return nil, ErrInvalidSnapshot
+	}
+	fs := make([]float64, n)
+	for i := range fs {
+		fs[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[1+8*i:]))
+	}
+	return fs, nil
+}
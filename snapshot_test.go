@@ -0,0 +1,169 @@
+package harmonica_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{5, 5, 0}, Gravity)
+	projectile.SetMass(3)
+	projectile.ApplyForce(Vector{1, 2, 3})
+
+	snap := projectile.Snapshot()
+	want := projectile.Clone()
+	for i := 0; i < fps; i++ {
+		projectile.Update()
+		want.Update()
+	}
+
+	projectile.Restore(snap)
+	for i := 0; i < fps; i++ {
+		projectile.Update()
+	}
+
+	if projectile.Snapshot() != want.Snapshot() {
+		t.Logf("Want: %+v", want.Snapshot())
+		t.Logf("Got:  %+v", projectile.Snapshot())
+		t.Fatal("restored projectile diverged")
+	}
+}
+
+func TestClone(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{5, 5, 0}, Gravity)
+	clone := projectile.Clone()
+	clone.AddForce(Wind{Velocity: Vector{10, 0, 0}, Drag: 1})
+
+	for i := 0; i < fps; i++ {
+		clone.Update()
+	}
+
+	if projectile.Position() != (Point{0, 0, 0}) {
+		t.Fatal("updating a clone moved the original")
+	}
+
+	projectile.Update()
+	if vel := projectile.Velocity(); !equal(vel.X, 5) {
+		t.Fatal("clone's forces leaked into the original")
+	}
+}
+
+func TestSnapshotEncoding(t *testing.T) {
+	projectile := NewProjectile(FPS(fps), Point{1, 2, 3}, Vector{4, 5, 6}, TerminalGravity)
+	projectile.SetMass(2.5)
+	projectile.Update()
+	snap := projectile.Snapshot()
+
+	b, err := snap.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary ProjectileSnapshot
+	if err := fromBinary.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if fromBinary != snap {
+		t.Fatalf("binary round trip: got %+v, want %+v", fromBinary, snap)
+	}
+
+	j, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON ProjectileSnapshot
+	if err := json.Unmarshal(j, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON != snap {
+		t.Fatalf("JSON round trip: got %+v, want %+v", fromJSON, snap)
+	}
+
+	if err := fromBinary.UnmarshalBinary(b[:len(b)-1]); err != ErrInvalidSnapshot {
+		t.Fatalf("expected ErrInvalidSnapshot, got %v", err)
+	}
+}
+
+func TestRigidBodySnapshot(t *testing.T) {
+	body := NewRigidBody(FPS(fps), Point{0, 0, 0}, Vector{5, 5, 0}, Gravity, 2, BoxInertia(2, 4, 2))
+	body.SetAngularVelocity(1)
+	body.ApplyForceAtPoint(Vector{0, 3, 0}, Point{2, 0, 0})
+
+	snap := body.Snapshot()
+	want := body.Clone()
+	for i := 0; i < fps; i++ {
+		want.Update()
+	}
+
+	body.SetAngle(3)
+	body.SetAngularVelocity(-2)
+	body.SetInertia(10)
+	body.ApplyTorque(5)
+	body.Restore(snap)
+	for i := 0; i < fps; i++ {
+		body.Update()
+	}
+
+	if body.Snapshot() != want.Snapshot() {
+		t.Logf("Want: %+v", want.Snapshot())
+		t.Logf("Got:  %+v", body.Snapshot())
+		t.Fatal("restored rigid body diverged")
+	}
+
+	b, err := snap.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromBinary RigidBodySnapshot
+	if err := fromBinary.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if fromBinary != snap {
+		t.Fatalf("binary round trip: got %+v, want %+v", fromBinary, snap)
+	}
+
+	j, err := json.Marshal(snap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON RigidBodySnapshot
+	if err := json.Unmarshal(j, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if fromJSON != snap {
+		t.Fatalf("JSON round trip: got %+v, want %+v", fromJSON, snap)
+	}
+
+	// A projectile snapshot isn't a rigid body snapshot.
+	pb, _ := snap.ProjectileSnapshot.MarshalBinary()
+	if err := fromBinary.UnmarshalBinary(pb); err != ErrInvalidSnapshot {
+		t.Fatalf("expected ErrInvalidSnapshot, got %v", err)
+	}
+}
+
+func TestSpringState(t *testing.T) {
+	spring := NewSpring(FPS(fps), 6, 0.2)
+	state := SpringState{Position: 0, Velocity: 0}
+	pos, vel := 0.0, 0.0
+
+	for i := 0; i < fps; i++ {
+		state = state.Update(spring, 100)
+		pos, vel = spring.Update(pos, vel, 100)
+	}
+	if state.Position != pos || state.Velocity != vel {
+		t.Fatal("spring state diverged from Spring.Update")
+	}
+
+	b, err := state.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded SpringState
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != state {
+		t.Fatalf("binary round trip: got %+v, want %+v", decoded, state)
+	}
+}
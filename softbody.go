@@ -0,0 +1,297 @@
+package harmonica
+
+// This file defines soft bodies: networks of point masses linked to each other
+// by damped springs. They're good for jelly-like elements, cloth, and anything
+// else that should wobble and deform as a whole.
+//
+// Unlike Spring, which pulls a single value towards a fixed target, the links
+// here pull masses towards each other following Hooke's law. Integration is
+// substepped so that stiff links stay stable at ordinary frame rates.
+//
+// Example usage:
+//
+//    // Run once to initialize a 20x10 cloth hanging from its top edge.
+//    cloth := NewCloth(FPS(60), TerminalGravity, Point{0, 0, 0}, 20, 10, 1.0, 0.1, 50, 0.5)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        cloth.Update()
+//        for i := 0; i < cloth.Len(); i++ {
+//            draw(cloth.Position(i))
+//        }
+//    })
+//
+// For background on mass-spring systems see:
+// https://en.wikipedia.org/wiki/Soft-body_dynamics#Spring/mass_models
+
+import "math"
+
+// SoftBody simulates a set of point masses connected by damped springs.
+type SoftBody struct {
+	nodes     []softNode
+	links     []softLink
+	gravity   Vector
+	deltaTime float64
+	substeps  int // set with SetSubsteps; zero picks automatically
+	stable    int // cached automatic substeps; zero when stale
+}
+
+type softNode struct {
+	pos    Point
+	vel    Vector
+	force  Vector
+	mass   float64
+	pinned bool
+}
+
+type softLink struct {
+	a, b      int
+	length    float64
+	stiffness float64
+	damping   float64
+}
+
+// NewSoftBody creates an empty soft body. It accepts a time delta and the
+// gravity acting on all masses, such as Gravity or TerminalGravity.
+func NewSoftBody(deltaTime float64, gravity Vector) *SoftBody {
+	return &SoftBody{
+		gravity:   gravity,
+		deltaTime: deltaTime,
+	}
+}
+
+// NewSoftBodyGrid creates a grid of masses, linked to their horizontal,
+// vertical and diagonal neighbors. It's a good starting point for
+// jelly-like elements.
+//
+// The grid's first row starts at the origin. Columns run along the X axis and
+// rows run in the direction of gravity, or along the Y axis without gravity.
+// Nodes are indexed row by row, so the node at a column and row has the index
+// row*cols + col.
+func NewSoftBodyGrid(deltaTime float64, gravity Vector, origin Point, cols, rows int, spacing, mass, stiffness, damping float64) *SoftBody {
+	b := NewSoftBody(deltaTime, gravity)
+	b.grid(origin, cols, rows, spacing, mass)
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			i := r*cols + c
+			if c+1 < cols {
+				b.Link(i, i+1, stiffness, damping)
+			}
+			if r+1 < rows {
+				b.Link(i, i+cols, stiffness, damping)
+			}
+			if c+1 < cols && r+1 < rows {
+				b.Link(i, i+cols+1, stiffness, damping)
+				b.Link(i+1, i+cols, stiffness, damping)
+			}
+		}
+	}
+	return b
+}
+
+// NewCloth creates a grid of masses laid out like NewSoftBodyGrid, with the
+// first row pinned in place. In addition to the grid's links, every mass is
+// linked to the masses two steps away, which keeps the cloth from folding up
+// too easily.
+func NewCloth(deltaTime float64, gravity Vector, origin Point, cols, rows int, spacing, mass, stiffness, damping float64) *SoftBody {
+	b := NewSoftBodyGrid(deltaTime, gravity, origin, cols, rows, spacing, mass, stiffness, damping)
+	for c := 0; c < cols; c++ {
+		b.SetPinned(c, true)
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			i := r*cols + c
+			if c+2 < cols {
+				b.Link(i, i+2, stiffness, damping)
+			}
+			if r+2 < rows {
+				b.Link(i, i+2*cols, stiffness, damping)
+			}
+		}
+	}
+	return b
+}
+
+// grid adds a grid of unlinked masses.
+func (b *SoftBody) grid(origin Point, cols, rows int, spacing, mass float64) {
+	down := b.gravity.Normalize()
+	if down == (Vector{}) {
+		down = Vector{0, 1, 0}
+	}
+	right := Vector{1, 0, 0}
+	if math.Abs(down.X) > 1-epsilon {
+		right = Vector{0, 1, 0}
+	}
+
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			pos := origin.
+				Add(right.Scale(float64(c) * spacing)).
+				Add(down.Scale(float64(r) * spacing))
+			b.AddNode(pos, mass, false)
+		}
+	}
+}
+
+// AddNode adds a mass at rest and returns its index. Masses must be positive;
+// other values are treated as 1.
+func (b *SoftBody) AddNode(pos Point, mass float64, pinned bool) int {
+	if mass <= 0 {
+		mass = 1
+	}
+	b.nodes = append(b.nodes, softNode{pos: pos, mass: mass, pinned: pinned})
+	b.stable = 0
+	return len(b.nodes) - 1
+}
+
+// Link connects two masses with a damped spring whose rest length is their
+// current distance from one another. Stiffness is the spring constant and
+// damping resists the masses moving towards or away from each other.
+func (b *SoftBody) Link(a, c int, stiffness, damping float64) {
+	b.LinkLength(a, c, b.nodes[a].pos.Distance(b.nodes[c].pos), stiffness, damping)
+}
+
+// LinkLength connects two masses with a damped spring of the given rest
+// length.
+func (b *SoftBody) LinkLength(a, c int, length, stiffness, damping float64) {
+	b.links = append(b.links, softLink{
+		a:         a,
+		b:         c,
+		length:    length,
+		stiffness: math.Max(0, stiffness),
+		damping:   math.Max(0, damping),
+	})
+	b.stable = 0
+}
+
+// Len returns the number of masses.
+func (b *SoftBody) Len() int {
+	return len(b.nodes)
+}
+
+// Position returns the position of a mass.
+func (b *SoftBody) Position(i int) Point {
+	return b.nodes[i].pos
+}
+
+// Velocity returns the velocity of a mass.
+func (b *SoftBody) Velocity(i int) Vector {
+	return b.nodes[i].vel
+}
+
+// SetPosition moves a mass. Use it to drag pinned masses around.
+func (b *SoftBody) SetPosition(i int, pos Point) {
+	b.nodes[i].pos = pos
+}
+
+// SetPinned pins a mass in place or frees it.
+func (b *SoftBody) SetPinned(i int, pinned bool) {
+	b.nodes[i].pinned = pinned
+	if pinned {
+		b.nodes[i].vel = Vector{}
+	}
+}
+
+// ApplyImpulse applies an impulse to a mass, changing its velocity
+// immediately in proportion to its mass. Pinned masses are unaffected.
+func (b *SoftBody) ApplyImpulse(i int, impulse Vector) {
+	n := &b.nodes[i]
+	if !n.pinned {
+		n.vel = n.vel.Add(impulse.Scale(1 / n.mass))
+	}
+}
+
+// SetGravity sets the gravity acting on all masses.
+func (b *SoftBody) SetGravity(gravity Vector) {
+	b.gravity = gravity
+}
+
+// SetSubsteps sets the number of integration steps per update. By default
+// it's picked automatically based on the stiffest link and lightest mass,
+// which keeps the simulation stable. Pass zero to go back to the default.
+func (b *SoftBody) SetSubsteps(n int) {
+	if n < 0 {
+		n = 0
+	}
+	b.substeps = n
+}
+
+// Substeps returns the number of integration steps per update.
+func (b *SoftBody) Substeps() int {
+	if b.substeps > 0 {
+		return b.substeps
+	}
+	if b.stable == 0 {
+		b.stable = b.stableSubsteps()
+	}
+	return b.stable
+}
+
+// stableSubsteps estimates how many substeps are needed to keep the fastest
+// oscillation in the network stable under semi-implicit Euler integration.
+func (b *SoftBody) stableSubsteps() int {
+	stiffness := make([]float64, len(b.nodes))
+	damping := make([]float64, len(b.nodes))
+	for _, l := range b.links {
+		stiffness[l.a] += l.stiffness
+		stiffness[l.b] += l.stiffness
+		damping[l.a] += l.damping
+		damping[l.b] += l.damping
+	}
+
+	// Semi-implicit Euler is stable while h·ω < 2; we aim well below that for
+	// accuracy and to leave room for damping.
+	const maxStep = 0.5
+
+	var worst float64
+	for i, n := range b.nodes {
+		omega := math.Sqrt(stiffness[i]/n.mass) + damping[i]/n.mass
+		worst = math.Max(worst, omega*b.deltaTime)
+	}
+	return int(math.Max(1, math.Ceil(worst/maxStep)))
+}
+
+// Update advances the simulation by one time step.
+func (b *SoftBody) Update() {
+	n := b.Substeps()
+	h := b.deltaTime / float64(n)
+	for ; n > 0; n-- {
+		b.step(h)
+	}
+}
+
+// step advances the simulation with semi-implicit Euler integration.
+func (b *SoftBody) step(h float64) {
+	for i := range b.nodes {
+		b.nodes[i].force = b.gravity.Scale(b.nodes[i].mass)
+	}
+
+	for _, l := range b.links {
+		na, nb := &b.nodes[l.a], &b.nodes[l.b]
+
+		d := nb.pos.Sub(na.pos)
+		dist := d.Length()
+		if dist < epsilon {
+			continue
+		}
+		dir := d.Scale(1 / dist)
+
+		// Hooke's law, plus damping along the link.
+		f := l.stiffness*(dist-l.length) + l.damping*nb.vel.Sub(na.vel).Dot(dir)
+		force := dir.Scale(f)
+
+		na.force = na.force.Add(force)
+		nb.force = nb.force.Sub(force)
+	}
+
+	for i := range b.nodes {
+		n := &b.nodes[i]
+		if n.pinned {
+			continue
+		}
+		n.vel = n.vel.Add(n.force.Scale(h / n.mass))
+		n.pos = n.pos.Add(n.vel.Scale(h))
+	}
+}
@@ -0,0 +1,85 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestSoftBodyHooke(t *testing.T) {
+	const (
+		mass      = 2.0
+		stiffness = 8.0
+	)
+	body := NewSoftBody(FPS(fps), Vector{})
+	a := body.AddNode(Point{0, 0, 0}, mass, true)
+	b := body.AddNode(Point{5, 0, 0}, mass, false)
+	body.LinkLength(a, b, 4, stiffness, 0)
+
+	// An undamped mass on a spring returns to its starting point after one
+	// period of 2π√(m/k).
+	period := 2 * math.Pi * math.Sqrt(mass/stiffness)
+	for i := 0; i < int(math.Round(period*fps)); i++ {
+		body.Update()
+	}
+
+	if p := body.Position(b); math.Abs(p.X-5) > 0.05 {
+		t.Logf("Want: %.2f", 5.0)
+		t.Logf("Got:  %.2f", p.X)
+		t.Fatal("position after one period unexpected")
+	}
+}
+
+func TestSoftBodyGrid(t *testing.T) {
+	body := NewSoftBodyGrid(FPS(fps), Vector{}, Point{0, 0, 0}, 4, 4, 1, 1, 200, 1)
+	if body.Len() != 16 {
+		t.Fatalf("expected 16 nodes, got %d", body.Len())
+	}
+
+	// Poke a corner and let it wobble.
+	body.ApplyImpulse(0, Vector{-5, -5, 0})
+	for i := 0; i < fps*10; i++ {
+		body.Update()
+	}
+
+	// It should have moved, but kept its shape.
+	if d := body.Position(0).Distance(body.Position(15)); math.Abs(d-3*math.Sqrt2) > 0.1 {
+		t.Logf("Want: %.2f", 3*math.Sqrt2)
+		t.Logf("Got:  %.2f", d)
+		t.Fatal("grid diagonal unexpected")
+	}
+	if body.Position(0) == (Point{0, 0, 0}) {
+		t.Fatal("impulse had no effect")
+	}
+}
+
+func TestCloth(t *testing.T) {
+	const cols, rows = 10, 8
+	cloth := NewCloth(FPS(fps), TerminalGravity, Point{0, 0, 0}, cols, rows, 1, 0.1, 500, 0.2)
+
+	if cloth.Substeps() < 2 {
+		t.Fatalf("expected stiff cloth to be substepped, got %d substeps", cloth.Substeps())
+	}
+
+	for i := 0; i < fps*10; i++ {
+		cloth.Update()
+	}
+
+	for c := 0; c < cols; c++ {
+		if p := cloth.Position(c); p != (Point{float64(c), 0, 0}) {
+			t.Fatalf("pinned node %d moved to %+v", c, p)
+		}
+	}
+	for i := 0; i < cloth.Len(); i++ {
+		p := cloth.Position(i)
+		if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.Abs(p.X) > 100 || math.Abs(p.Y) > 100 {
+			t.Fatalf("cloth blew up: node %d at %+v", i, p)
+		}
+	}
+
+	// The bottom row hangs below the top, stretched a little by gravity.
+	if p := cloth.Position((rows-1)*cols + cols/2); p.Y < rows-1 || p.Y > rows {
+		t.Fatalf("bottom of cloth unexpected: %+v", p)
+	}
+}
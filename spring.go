@@ -104,6 +104,11 @@ var epsilon = math.Nextafter(1, 2) - 1
 type Spring struct {
 	posPosCoef, posVelCoef float64
 	velPosCoef, velVelCoef float64
+
+	// The parameters the coefficients were computed from.
+	deltaTime        float64
+	angularFrequency float64
+	dampingRatio     float64
 }
 
 // NewSpring initializes a new Spring, computing the parameters needed to
@@ -134,6 +139,10 @@ func NewSpring(deltaTime, angularFrequency, dampingRatio float64) (s Spring) {
 	angularFrequency = math.Max(0.0, angularFrequency)
 	dampingRatio = math.Max(0.0, dampingRatio)
 
+	s.deltaTime = deltaTime
+	s.angularFrequency = angularFrequency
+	s.dampingRatio = dampingRatio
+
 	// If there is no angular frequency, the spring will not move and we can
 	// return identity.
 	if angularFrequency < epsilon {
@@ -222,3 +231,29 @@ func (s Spring) Update(pos, vel float64, equilibriumPos float64) (newPos, newVel
 
 	return newPos, newVel
 }
+
+// WithDeltaTime returns a spring with the same angular frequency and damping
+// ratio, but for a different time step. The spring's motion is the same over
+// time; only the size of each step changes.
+func (s Spring) WithDeltaTime(deltaTime float64) Spring {
+	if deltaTime == s.deltaTime {
+		return s
+	}
+	return NewSpring(deltaTime, s.angularFrequency, s.dampingRatio)
+}
+
+// DeltaTime returns the time step the spring was initialized with.
+func (s Spring) DeltaTime() float64 {
+	return s.deltaTime
+}
+
+// AngularFrequency returns the angular frequency the spring was initialized
+// with.
+func (s Spring) AngularFrequency() float64 {
+	return s.angularFrequency
+}
+
+// DampingRatio returns the damping ratio the spring was initialized with.
+func (s Spring) DampingRatio() float64 {
+	return s.dampingRatio
+}
@@ -0,0 +1,182 @@
+package harmonica
+
+// This file defines exporters that turn a spring into CSS, so the same spring
+// settings can drive animations on the web. The spring's response is sampled
+// as it moves from 0 to 1, including any overshoot, and simplified to as few
+// points as possible within a tolerance.
+//
+// Example usage:
+//
+//    spring := NewSpring(FPS(60), 6.0, 0.4)
+//    easing, duration := spring.LinearEasing(0.001)
+//    css := fmt.Sprintf("transition: left %dms %s;", duration.Milliseconds(), easing)
+//
+// For background on the CSS linear() function see:
+// https://developer.mozilla.org/en-US/docs/Web/CSS/easing-function
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxResponseSteps limits how many time steps are sampled when waiting for a
+// spring to settle, in case it's so heavily damped it barely moves.
+const maxResponseSteps = 100000
+
+// response returns the position and velocity of a spring moving from 0 to 1
+// at every time step, until it's within the tolerance of 1 and nearly still.
+// The last sample is always exactly at rest at 1.
+func (s Spring) response(tolerance float64) (positions, velocities []float64) {
+	positions = []float64{0}
+	velocities = []float64{0}
+	if s.angularFrequency < epsilon || s.deltaTime <= 0 {
+		// The spring never moves, so treat it as instant.
+		return append(positions, 1), append(velocities, 0)
+	}
+
+	tolerance = math.Max(epsilon, tolerance)
+
+	var pos, vel float64
+	for i := 0; i < maxResponseSteps; i++ {
+		pos, vel = s.Update(pos, vel, 1)
+
+		// Measure the distance from rest in terms of both position and
+		// velocity, so an underdamped spring isn't considered settled while
+		// it races through its target.
+		offset := pos - 1
+		speed := vel / s.angularFrequency
+		if math.Sqrt(offset*offset+speed*speed) < tolerance {
+			break
+		}
+		positions = append(positions, pos)
+		velocities = append(velocities, vel)
+	}
+	return append(positions, 1), append(velocities, 0)
+}
+
+// Duration returns how long the spring takes to move from one value to
+// another, until it's within the tolerance of the target, as a fraction of
+// the distance, and nearly still.
+func (s Spring) Duration(tolerance float64) time.Duration {
+	samples, _ := s.response(tolerance)
+	return s.responseDuration(len(samples))
+}
+
+// responseDuration returns the duration covered by the given number of
+// response samples.
+func (s Spring) responseDuration(samples int) time.Duration {
+	if s.angularFrequency < epsilon || s.deltaTime <= 0 {
+		return 0
+	}
+	return time.Duration(float64(samples-1) * s.deltaTime * float64(time.Second))
+}
+
+// LinearEasing returns a CSS linear() easing function that follows the
+// spring's motion, along with how long the motion takes. Use them together,
+// as the easing is normalized to the duration.
+//
+// The tolerance is the largest error allowed, as a fraction of the distance
+// moved; 0.001 is a good place to start. It determines both how many points
+// the easing has and when the spring is considered settled.
+func (s Spring) LinearEasing(tolerance float64) (easing string, duration time.Duration) {
+	samples, _ := s.response(tolerance)
+	points := simplifyResponse(samples, tolerance)
+
+	var b strings.Builder
+	b.WriteString("linear(")
+	for i, p := range points {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(cssNumber(samples[p]))
+
+		// The first and last stops are at 0% and 100% by default.
+		if i > 0 && i < len(points)-1 {
+			b.WriteString(" ")
+			b.WriteString(cssPercent(samples, p))
+		}
+	}
+	b.WriteString(")")
+
+	return b.String(), s.responseDuration(len(samples))
+}
+
+// Keyframes returns a CSS @keyframes rule with the given name, animating the
+// given property from one value to another along the spring's motion, along
+// with how long the motion takes. The unit, such as "px" or "%", is appended
+// to each value.
+//
+// Keyframes are interpolated linearly, so the animation using them should set
+// animation-timing-function to linear.
+func (s Spring) Keyframes(name, property string, from, to float64, unit string, tolerance float64) (keyframes string, duration time.Duration) {
+	samples, _ := s.response(tolerance)
+	points := simplifyResponse(samples, tolerance)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@keyframes %s {\n", name)
+	for _, p := range points {
+		v := from + (to-from)*samples[p]
+		fmt.Fprintf(&b, "  %s { %s: %s%s; }\n", cssPercent(samples, p), property, cssNumber(v), unit)
+	}
+	b.WriteString("}\n")
+
+	return b.String(), s.responseDuration(len(samples))
+}
+
+// simplifyResponse returns the indices of the fewest samples needed to
+// reproduce all the samples within the tolerance by interpolating linearly
+// between them, using the Ramer-Douglas-Peucker algorithm. The first and last
+// samples are always included.
+func simplifyResponse(samples []float64, tolerance float64) []int {
+	keep := make([]bool, len(samples))
+	keep[0] = true
+	keep[len(samples)-1] = true
+
+	var simplify func(from, to int)
+	simplify = func(from, to int) {
+		var (
+			worst    = -1
+			worstErr = tolerance
+		)
+		for i := from + 1; i < to; i++ {
+			// Compare vertically, since that's how the easing is evaluated.
+			t := float64(i-from) / float64(to-from)
+			if err := math.Abs(samples[i] - lerp(samples[from], samples[to], t)); err > worstErr {
+				worst, worstErr = i, err
+			}
+		}
+		if worst < 0 {
+			return
+		}
+		keep[worst] = true
+		simplify(from, worst)
+		simplify(worst, to)
+	}
+	simplify(0, len(samples)-1)
+
+	var points []int
+	for i, k := range keep {
+		if k {
+			points = append(points, i)
+		}
+	}
+	return points
+}
+
+// cssPercent formats the position of a sample in the response as a CSS
+// percentage.
+func cssPercent(samples []float64, i int) string {
+	return cssNumber(float64(i)/float64(len(samples)-1)*100) + "%"
+}
+
+// cssNumber formats a number compactly for CSS, to four decimal places.
+func cssNumber(v float64) string {
+	v = math.Round(v*10000) / 10000
+	if v == 0 {
+		v = 0 // avoid "-0"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
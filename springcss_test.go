@@ -0,0 +1,101 @@
+package harmonica_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestSpringLinearEasing(t *testing.T) {
+	const tolerance = 0.001
+
+	for _, tc := range []struct {
+		frequency, damping float64
+	}{
+		{6, 0.2},
+		{6, 1},
+		{4, 2},
+	} {
+		s := NewSpring(FPS(fps), tc.frequency, tc.damping)
+		css, duration := s.LinearEasing(tolerance)
+		if duration != s.Duration(tolerance) || duration <= 0 {
+			t.Fatalf("unexpected duration %s", duration)
+		}
+
+		// The easing round-trips through the CSS parser and follows the
+		// spring closely.
+		ease, err := ParseTimingFunction(css)
+		if err != nil {
+			t.Fatalf("%s: %v", css, err)
+		}
+		steps := int(math.Round(duration.Seconds() * fps))
+		var pos, vel float64
+		for i := 1; i < steps; i++ {
+			pos, vel = s.Update(pos, vel, 1)
+			if got := ease(float64(i) / float64(steps)); math.Abs(got-pos) > tolerance*5 {
+				t.Logf("Want: %.4f", pos)
+				t.Logf("Got:  %.4f", got)
+				t.Fatalf("easing for %v diverged at step %d of %d", tc, i, steps)
+			}
+		}
+		if ease(1) != 1 {
+			t.Fatalf("expected easing to end at 1, got %.4f", ease(1))
+		}
+
+		// It's much more compact than a stop per frame.
+		if stops := strings.Count(css, ",") + 1; stops > steps/2 {
+			t.Fatalf("expected the easing to be simplified, got %d stops for %d frames", stops, steps)
+		}
+	}
+}
+
+func TestSpringOvershoot(t *testing.T) {
+	css, _ := NewSpring(FPS(fps), 6, 0.2).LinearEasing(0.001)
+	ease, err := ParseTimingFunction(css)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var peak float64
+	for i := 0; i <= 100; i++ {
+		peak = math.Max(peak, ease(float64(i)/100))
+	}
+	if peak < 1.3 {
+		t.Fatalf("expected an underdamped spring to overshoot, peaked at %.3f", peak)
+	}
+}
+
+func TestSpringKeyframes(t *testing.T) {
+	s := NewSpring(FPS(fps), 6, 0.5)
+	css, duration := s.Keyframes("slide", "left", 10, 110, "px", 0.01)
+	if duration != s.Duration(0.01) {
+		t.Fatalf("unexpected duration %s", duration)
+	}
+
+	lines := strings.Split(strings.TrimSpace(css), "\n")
+	if lines[0] != "@keyframes slide {" || lines[len(lines)-1] != "}" {
+		t.Fatalf("malformed keyframes:\n%s", css)
+	}
+	if first := lines[1]; first != "  0% { left: 10px; }" {
+		t.Fatalf("unexpected first keyframe %q", first)
+	}
+	if last := lines[len(lines)-2]; last != "  100% { left: 110px; }" {
+		t.Fatalf("unexpected last keyframe %q", last)
+	}
+}
+
+func TestSpringDurationStill(t *testing.T) {
+	if d := NewSpring(FPS(fps), 0, 1).Duration(0.001); d != 0 {
+		t.Fatalf("expected a spring that doesn't move to take no time, got %s", d)
+	}
+
+	// Stiffer springs settle faster.
+	slow := NewSpring(FPS(fps), 4, 1).Duration(0.001)
+	fast := NewSpring(FPS(fps), 12, 1).Duration(0.001)
+	if fast >= slow || slow > 5*time.Second {
+		t.Fatalf("unexpected durations: %s for the stiff spring, %s for the soft one", fast, slow)
+	}
+}
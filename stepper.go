@@ -0,0 +1,113 @@
+package harmonica
+
+// This file defines a fixed time step clock. Springs and projectiles are
+// initialized with a time delta and behave best when they're always updated
+// with that same delta, but real frames rarely arrive on schedule. A Stepper
+// accumulates the time that has really passed and runs as many fixed size
+// steps as fit, carrying the remainder over to the next frame.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    deltaTime := FPS(60)
+//    spring := NewSpring(deltaTime, 6.0, 0.5)
+//    stepper := NewStepper(deltaTime, DefaultMaxSteps)
+//
+//    // Update on every frame, however often that is.
+//    someUpdateLoop(func() {
+//        stepper.Tick(time.Now(), func(float64) {
+//            prevPos = pos
+//            pos, vel = spring.Update(pos, vel, target)
+//        })
+//
+//        // Render between the last two steps.
+//        draw(prevPos + (pos-prevPos)*stepper.Alpha())
+//    })
+//
+// For background on the technique see:
+// https://gafferongames.com/post/fix_your_timestep/
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultMaxSteps is a reasonable number of steps to allow per frame.
+const DefaultMaxSteps = 8
+
+// Stepper runs fixed size time steps to keep up with real elapsed time.
+type Stepper struct {
+	deltaTime   float64
+	maxSteps    int
+	accumulator float64
+	last        time.Time
+}
+
+// NewStepper creates a new stepper. It accepts the time delta to step by and
+// the maximum number of steps to run per frame.
+//
+// The maximum keeps a simulation that can't keep up in real time from falling
+// further and further behind, each frame having more steps to run than the
+// last. When it's reached, the simulation slows down instead.
+func NewStepper(deltaTime float64, maxSteps int) *Stepper {
+	if maxSteps < 1 {
+		maxSteps = 1
+	}
+	return &Stepper{
+		deltaTime: math.Max(epsilon, deltaTime),
+		maxSteps:  maxSteps,
+	}
+}
+
+// Advance adds the given number of elapsed seconds and calls step for each
+// time step that's now due, passing it the time delta. It returns the number
+// of steps run.
+func (s *Stepper) Advance(elapsed float64, step func(deltaTime float64)) int {
+	s.accumulator += math.Max(0, elapsed)
+
+	var n int
+	for s.accumulator >= s.deltaTime {
+		if n == s.maxSteps {
+			// Drop the time we can't catch up on, keeping the fraction of
+			// a step so rendering stays smooth.
+			s.accumulator = math.Mod(s.accumulator, s.deltaTime)
+			break
+		}
+		step(s.deltaTime)
+		s.accumulator -= s.deltaTime
+		n++
+	}
+	return n
+}
+
+// Tick is like Advance, but measures the elapsed time since the last tick
+// itself. The first tick runs no steps.
+func (s *Stepper) Tick(now time.Time, step func(deltaTime float64)) int {
+	if s.last.IsZero() {
+		s.last = now
+		return 0
+	}
+	elapsed := now.Sub(s.last).Seconds()
+	s.last = now
+	return s.Advance(elapsed, step)
+}
+
+// Alpha returns how far, from 0 to 1, real time has moved past the last step
+// towards the next. Use it to interpolate between the state before the last
+// step and the state after it when rendering, which hides the uneven spacing
+// between steps.
+func (s *Stepper) Alpha() float64 {
+	return math.Min(1, s.accumulator/s.deltaTime)
+}
+
+// DeltaTime returns the size of each time step.
+func (s *Stepper) DeltaTime() float64 {
+	return s.deltaTime
+}
+
+// Reset discards accumulated time and forgets the last tick, such as after
+// pausing.
+func (s *Stepper) Reset() {
+	s.accumulator = 0
+	s.last = time.Time{}
+}
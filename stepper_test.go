@@ -0,0 +1,66 @@
+package harmonica_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestStepperAdvance(t *testing.T) {
+	s := NewStepper(0.01, DefaultMaxSteps)
+
+	var total float64
+	step := func(dt float64) { total += dt }
+
+	if n := s.Advance(0.025, step); n != 2 {
+		t.Fatalf("expected 2 steps, got %d", n)
+	}
+	if !equal(s.Alpha(), 0.5) {
+		t.Fatalf("expected alpha 0.5, got %.2f", s.Alpha())
+	}
+
+	// The remainder carries over.
+	if n := s.Advance(0.006, step); n != 1 {
+		t.Fatalf("expected 1 step, got %d", n)
+	}
+	if !equal(total, 0.03) {
+		t.Fatalf("expected 0.03s simulated, got %.3f", total)
+	}
+}
+
+func TestStepperMaxSteps(t *testing.T) {
+	s := NewStepper(0.01, 4)
+
+	// A long stall runs no more than the maximum, and the backlog doesn't
+	// carry over.
+	if n := s.Advance(1.005, func(float64) {}); n != 4 {
+		t.Fatalf("expected 4 steps, got %d", n)
+	}
+	if n := s.Advance(0.01, func(float64) {}); n != 1 {
+		t.Fatalf("expected 1 step after a stall, got %d", n)
+	}
+}
+
+func TestStepperTick(t *testing.T) {
+	s := NewStepper(FPS(fps), DefaultMaxSteps)
+	now := time.Now()
+
+	var steps int
+	step := func(float64) { steps++ }
+
+	s.Tick(now, step)
+	for i := 1; i <= 100; i++ {
+		// Frames arrive unevenly, but average out to 30 per second.
+		jitter := time.Duration(i%3-1) * 5 * time.Millisecond
+		s.Tick(now.Add(time.Duration(i)*time.Second/30+jitter), step)
+	}
+
+	// About 100/30 seconds at 60 steps a second.
+	if steps < 198 || steps > 202 {
+		t.Fatalf("expected about 200 steps, got %d", steps)
+	}
+	if a := s.Alpha(); a < 0 || a > 1 {
+		t.Fatalf("alpha out of range: %.2f", a)
+	}
+}
@@ -0,0 +1,236 @@
+package harmonica
+
+// This file defines continuous collision detection. Rather than checking for
+// overlap after a projectile has moved, the projectile's path over a step is
+// swept against obstacles to find exactly when it first touches one. This
+// keeps fast projectiles from tunneling through thin walls at low frame rates.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    ball := NewProjectile(FPS(20), Point{0, 0, 0}, Vector{80, 0, 0}, TerminalGravity)
+//    floor := Plane{Point: Point{0, 24, 0}, Normal: Vector{0, -1, 0}}
+//    wall := Box{Min: Point{30, 0, 0}, Max: Point{31, 24, 0}}
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        _, hit, ok := ball.UpdateSwept(0.5, floor, wall)
+//        if ok {
+//            ball.SetVelocity(hit.Bounce(ball.Velocity(), 0.8))
+//        }
+//    })
+
+import "math"
+
+// Obstacle is something a moving projectile can run into.
+type Obstacle interface {
+	// Sweep moves a sphere of the given radius from a point along a motion
+	// vector and reports the first time it touches the obstacle, if it does.
+	Sweep(from Point, motion Vector, radius float64) (Hit, bool)
+}
+
+// Hit describes where a swept projectile touched an obstacle.
+type Hit struct {
+	// Time is the fraction of the motion completed at the time of impact,
+	// from 0 to 1.
+	Time float64
+
+	// Point is the position of the projectile's center at the time of impact.
+	Point Point
+
+	// Normal is the unit surface normal of the obstacle at the point of
+	// contact, pointing towards the projectile.
+	Normal Vector
+}
+
+// Bounce returns a velocity reflected off the surface that was hit. The
+// restitution determines how much of the velocity into the surface is kept,
+// from 0 to stop dead against it, to 1 for a perfect bounce.
+func (h Hit) Bounce(vel Vector, restitution float64) Vector {
+	vn := vel.Dot(h.Normal)
+	if vn >= 0 {
+		return vel
+	}
+	return vel.Sub(h.Normal.Scale((1 + restitution) * vn))
+}
+
+// Sweep moves a sphere of the given radius from a point along a motion vector
+// and reports the first obstacle it touches, if any.
+func Sweep(from Point, motion Vector, radius float64, obstacles ...Obstacle) (hit Hit, ok bool) {
+	hit.Time = math.Inf(1)
+	for _, o := range obstacles {
+		if h, hitOK := o.Sweep(from, motion, radius); hitOK && h.Time < hit.Time {
+			hit, ok = h, true
+		}
+	}
+	return hit, ok
+}
+
+// UpdateSwept updates the projectile like Update, treating it as a sphere of
+// the given radius. If it runs into an obstacle along the way, it stops at the
+// point of impact and the hit is returned. The projectile's velocity isn't
+// changed by the impact; use Hit.Bounce to make it bounce.
+func (p *Projectile) UpdateSwept(radius float64, obstacles ...Obstacle) (Point, Hit, bool) {
+	return p.updateSwept(radius, obstacles, p.Update)
+}
+
+// updateSwept sweeps the projectile along its motion for the next step, then
+// calls update to take the step.
+func (p *Projectile) updateSwept(radius float64, obstacles []Obstacle, update func() Point) (Point, Hit, bool) {
+	from := p.pos
+	motion := p.vel.Scale(p.deltaTime)
+
+	hit, ok := Sweep(from, motion, radius, obstacles...)
+	update()
+	if ok {
+		p.pos = hit.Point
+	}
+	return p.pos, hit, ok
+}
+
+// Plane is an infinite flat obstacle, like a floor or a wall. Everything
+// behind the plane, opposite the direction of its normal, is solid.
+type Plane struct {
+	Point  Point
+	Normal Vector
+}
+
+// Sweep implements Obstacle.
+func (o Plane) Sweep(from Point, motion Vector, radius float64) (Hit, bool) {
+	n := o.Normal.Normalize()
+	if n == (Vector{}) {
+		return Hit{}, false
+	}
+
+	// Only motion into the plane can hit it.
+	approach := motion.Dot(n)
+	if approach >= 0 {
+		return Hit{}, false
+	}
+
+	gap := from.Sub(o.Point).Dot(n) - radius
+	t := math.Max(0, gap/-approach)
+	if t > 1 {
+		return Hit{}, false
+	}
+	return Hit{Time: t, Point: from.Add(motion.Scale(t)), Normal: n}, true
+}
+
+// Circle is a round obstacle: a circle in 2D or a sphere in 3D.
+type Circle struct {
+	Center Point
+	Radius float64
+}
+
+// Sweep implements Obstacle.
+func (o Circle) Sweep(from Point, motion Vector, radius float64) (Hit, bool) {
+	// Sweeping a sphere against a sphere is the same as sweeping a point
+	// against a sphere with the combined radius.
+	var (
+		r = o.Radius + radius
+		m = from.Sub(o.Center)
+		a = motion.LengthSquared()
+		b = m.Dot(motion)
+		c = m.LengthSquared() - r*r
+	)
+
+	// Only motion towards the center can hit it.
+	if b >= 0 || a < epsilon {
+		return Hit{}, false
+	}
+
+	t := 0.0
+	if c > 0 {
+		disc := b*b - a*c
+		if disc < 0 {
+			return Hit{}, false
+		}
+		t = (-b - math.Sqrt(disc)) / a
+		if t > 1 {
+			return Hit{}, false
+		}
+	}
+
+	p := from.Add(motion.Scale(t))
+	return Hit{Time: t, Point: p, Normal: p.Sub(o.Center).Normalize()}, true
+}
+
+// Box is an axis-aligned rectangular obstacle: a rectangle in 2D or a cuboid in
+// 3D. In 2D contexts leave the Z coordinates of both corners at zero.
+//
+// For simplicity the box's corners are treated as square rather than rounded
+// when sweeping projectiles with a radius, so projectiles may stop a little
+// short of a corner.
+type Box struct {
+	Min, Max Point
+}
+
+// Sweep implements Obstacle.
+func (o Box) Sweep(from Point, motion Vector, radius float64) (Hit, bool) {
+	var (
+		start = [3]float64{from.X, from.Y, from.Z}
+		delta = [3]float64{motion.X, motion.Y, motion.Z}
+		lo    = [3]float64{o.Min.X - radius, o.Min.Y - radius, o.Min.Z - radius}
+		hi    = [3]float64{o.Max.X + radius, o.Max.Y + radius, o.Max.Z + radius}
+
+		enter  = math.Inf(-1)
+		exit   = math.Inf(1)
+		axis   = -1
+		normal float64
+	)
+
+	// Intersect the slabs between each pair of faces, using the box expanded
+	// by the radius.
+	for i := 0; i < 3; i++ {
+		if math.Abs(delta[i]) < epsilon {
+			if start[i] < lo[i] || start[i] > hi[i] {
+				return Hit{}, false
+			}
+			continue
+		}
+
+		t0 := (lo[i] - start[i]) / delta[i]
+		t1 := (hi[i] - start[i]) / delta[i]
+		n := -1.0
+		if t0 > t1 {
+			t0, t1 = t1, t0
+			n = 1
+		}
+		if t0 > enter {
+			enter, axis, normal = t0, i, n
+		}
+		exit = math.Min(exit, t1)
+	}
+
+	if enter > exit || exit <= 0 || enter > 1 {
+		return Hit{}, false
+	}
+
+	// Not moving at all.
+	if axis < 0 {
+		return Hit{}, false
+	}
+
+	// Starting inside the box counts as an immediate hit on the face the
+	// projectile last crossed.
+	if enter < 0 {
+		enter = 0
+	}
+
+	var n Vector
+	switch axis {
+	case 0:
+		n.X = normal
+	case 1:
+		n.Y = normal
+	case 2:
+		n.Z = normal
+	}
+
+	// Moving away from the face means we're leaving, not hitting.
+	if motion.Dot(n) >= 0 {
+		return Hit{}, false
+	}
+
+	return Hit{Time: enter, Point: from.Add(motion.Scale(enter)), Normal: n}, true
+}
@@ -0,0 +1,97 @@
+package harmonica_test
+
+import (
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestSweepPlane(t *testing.T) {
+	floor := Plane{Point: Point{0, 0, 0}, Normal: Vector{0, 1, 0}}
+
+	hit, ok := floor.Sweep(Point{0, 10, 0}, Vector{4, -20, 0}, 1)
+	if !ok {
+		t.Fatal("expected a hit")
+	}
+	if !equal(hit.Time, 0.45) || !equal(hit.Point.X, 1.8) || !equal(hit.Point.Y, 1) || hit.Normal != (Vector{0, 1, 0}) {
+		t.Fatalf("hit unexpected: %+v", hit)
+	}
+
+	if _, ok := floor.Sweep(Point{0, 10, 0}, Vector{0, -5, 0}, 1); ok {
+		t.Fatal("expected motion that falls short to miss")
+	}
+	if _, ok := floor.Sweep(Point{0, 10, 0}, Vector{0, 50, 0}, 1); ok {
+		t.Fatal("expected motion away from the plane to miss")
+	}
+}
+
+func TestSweepCircle(t *testing.T) {
+	c := Circle{Center: Point{10, 0, 0}, Radius: 2}
+
+	hit, ok := c.Sweep(Point{0, 0, 0}, Vector{20, 0, 0}, 1)
+	if !ok {
+		t.Fatal("expected a hit")
+	}
+	if !equal(hit.Time, 0.35) || !equal(hit.Point.X, 7) || hit.Normal != (Vector{-1, 0, 0}) {
+		t.Fatalf("hit unexpected: %+v", hit)
+	}
+
+	if _, ok := c.Sweep(Point{0, 5, 0}, Vector{20, 0, 0}, 1); ok {
+		t.Fatal("expected motion passing by to miss")
+	}
+}
+
+func TestSweepBox(t *testing.T) {
+	b := Box{Min: Point{10, -5, 0}, Max: Point{11, 5, 0}}
+
+	hit, ok := b.Sweep(Point{0, 0, 0}, Vector{40, 0, 0}, 0.5)
+	if !ok {
+		t.Fatal("expected a hit")
+	}
+	if !equal(hit.Time, 0.2375) || !equal(hit.Point.X, 9.5) || hit.Normal != (Vector{-1, 0, 0}) {
+		t.Fatalf("hit unexpected: %+v", hit)
+	}
+
+	if _, ok := b.Sweep(Point{0, 8, 0}, Vector{40, 0, 0}, 0.5); ok {
+		t.Fatal("expected motion passing over to miss")
+	}
+}
+
+func TestUpdateSwept(t *testing.T) {
+	// At 10 FPS this projectile moves 10 units per frame, so it would skip
+	// right over a wall one unit thick.
+	wall := Box{Min: Point{15, -10, 0}, Max: Point{16, 10, 0}}
+	p := NewProjectile(FPS(10), Point{0, 0, 0}, Vector{100, 0, 0}, Vector{})
+
+	var hits int
+	for i := 0; i < 5; i++ {
+		pos, hit, ok := p.UpdateSwept(0, wall)
+		if ok {
+			hits++
+			if !equal(pos.X, 15) {
+				t.Fatalf("expected projectile to stop at the wall, got %+v", pos)
+			}
+			p.SetVelocity(hit.Bounce(p.Velocity(), 1))
+		}
+		if pos.X > 15 {
+			t.Fatalf("projectile tunneled through the wall to %+v", pos)
+		}
+	}
+
+	if hits != 1 {
+		t.Fatalf("expected one hit, got %d", hits)
+	}
+	if vel := p.Velocity(); !equal(vel.X, -100) {
+		t.Fatalf("expected projectile to bounce back, got %+v", vel)
+	}
+}
+
+func TestSweepNearest(t *testing.T) {
+	near := Plane{Point: Point{5, 0, 0}, Normal: Vector{-1, 0, 0}}
+	far := Circle{Center: Point{20, 0, 0}, Radius: 1}
+
+	hit, ok := Sweep(Point{0, 0, 0}, Vector{30, 0, 0}, 0, far, near)
+	if !ok || !equal(hit.Point.X, 5) {
+		t.Fatalf("expected to hit the nearest obstacle, got %+v", hit)
+	}
+}
@@ -0,0 +1,145 @@
+package harmonica
+
+// This file defines a time source, which controls how quickly time passes for
+// the simulations driven by it. It's made for debugging and demos: slow
+// everything down, pause it, and step through one frame at a time.
+//
+// Example usage:
+//
+//    // Run once to initialize.
+//    clock := NewTimeSource(FPS(60))
+//    spring := NewSpring(FPS(60), 6.0, 0.5)
+//    clock.SetScale(0.1) // slow motion
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        clock.Advance()
+//        pos, vel = clock.Spring(spring).Update(pos, vel, target)
+//        projectilePos := projectile.UpdateDelta(clock.DeltaTime())
+//    })
+//
+// Scaling time doesn't change how a spring behaves, only how quickly we move
+// through its motion: a spring at half speed follows exactly the same path,
+// taking twice as long.
+
+import "math"
+
+// maxCachedSprings is how many rescaled springs a TimeSource keeps at once
+// before starting a new cache. The previous cache is kept too, so springs in
+// constant use survive the switch.
+const maxCachedSprings = 64
+
+// TimeSource provides scaled, pausable time deltas for each frame.
+type TimeSource struct {
+	deltaTime float64
+	scale     float64
+	paused    bool
+	steps     int
+	current   float64
+	elapsed   float64
+
+	// Springs rescaled to the current time delta, keyed by the original.
+	// Once the cache is full it becomes the old cache and a new one is
+	// started, so it stays bounded when springs are created on the fly.
+	springs      map[Spring]Spring
+	oldSprings   map[Spring]Spring
+	springsDelta float64
+}
+
+// NewTimeSource creates a new time source running at normal speed. It accepts
+// the real time delta of each frame, as from FPS.
+func NewTimeSource(deltaTime float64) *TimeSource {
+	return &TimeSource{
+		deltaTime: math.Max(0, deltaTime),
+		scale:     1,
+	}
+}
+
+// Advance moves on to the next frame and returns its time delta, which is the
+// real time delta multiplied by the scale, or zero while paused. Call it once
+// per frame, before updating anything.
+func (ts *TimeSource) Advance() float64 {
+	switch {
+	case !ts.paused:
+		ts.current = ts.deltaTime * ts.scale
+	case ts.steps > 0:
+		ts.current = ts.deltaTime * ts.scale
+		ts.steps--
+	default:
+		ts.current = 0
+	}
+	ts.elapsed += ts.current
+	return ts.current
+}
+
+// DeltaTime returns the time delta of the current frame.
+func (ts *TimeSource) DeltaTime() float64 {
+	return ts.current
+}
+
+// Elapsed returns the total scaled time that has passed.
+func (ts *TimeSource) Elapsed() float64 {
+	return ts.elapsed
+}
+
+// Scale returns the time scale.
+func (ts *TimeSource) Scale() float64 {
+	return ts.scale
+}
+
+// SetScale sets how quickly time passes, where 1 is normal speed, 0.1 is ten
+// times slower and 2 is twice as fast. Negative values are treated as 0.
+func (ts *TimeSource) SetScale(scale float64) {
+	ts.scale = math.Max(0, scale)
+}
+
+// Pause stops time from passing until Resume is called.
+func (ts *TimeSource) Pause() {
+	ts.paused = true
+	ts.steps = 0
+}
+
+// Resume lets time pass again after Pause.
+func (ts *TimeSource) Resume() {
+	ts.paused = false
+	ts.steps = 0
+}
+
+// Paused returns whether time is paused.
+func (ts *TimeSource) Paused() bool {
+	return ts.paused
+}
+
+// StepFrame lets a single frame of time pass while paused. It has no effect
+// when not paused.
+func (ts *TimeSource) StepFrame() {
+	if ts.paused {
+		ts.steps++
+	}
+}
+
+// Spring returns s adjusted for the current frame's time delta. The result is
+// cached, so it's cheap to call for every spring on every frame.
+func (ts *TimeSource) Spring(s Spring) Spring {
+	if ts.current == s.deltaTime {
+		return s
+	}
+	if ts.springs == nil || ts.springsDelta != ts.current {
+		ts.springs = make(map[Spring]Spring)
+		ts.oldSprings = nil
+		ts.springsDelta = ts.current
+	}
+	if scaled, ok := ts.springs[s]; ok {
+		return scaled
+	}
+	scaled, ok := ts.oldSprings[s]
+	if !ok {
+		scaled = s.WithDeltaTime(ts.current)
+	}
+	if len(ts.springs) >= maxCachedSprings {
+		ts.oldSprings = ts.springs
+		ts.springs = make(map[Spring]Spring)
+	}
+	ts.springs[s] = scaled
+	return scaled
+}
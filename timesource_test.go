@@ -0,0 +1,90 @@
+package harmonica_test
+
+import (
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestTimeSourceScale(t *testing.T) {
+	ts := NewTimeSource(FPS(fps))
+	ts.SetScale(0.5)
+
+	// A spring at half speed follows the same path, taking twice as long.
+	var (
+		s              = NewSpring(FPS(fps), 6, 0.3)
+		slowPos, slowV float64
+		pos, vel       float64
+	)
+	for i := 0; i < fps; i++ {
+		ts.Advance()
+		slowPos, slowV = ts.Spring(s).Update(slowPos, slowV, 1)
+		if i%2 == 1 {
+			pos, vel = s.Update(pos, vel, 1)
+			if !equal(slowPos, pos) {
+				t.Logf("Want: %.4f", pos)
+				t.Logf("Got:  %.4f", slowPos)
+				t.Fatalf("slowed spring diverged on frame %d", i)
+			}
+		}
+	}
+	if !equal(ts.Elapsed(), 0.5) {
+		t.Fatalf("expected 0.5s to pass, got %.3f", ts.Elapsed())
+	}
+}
+
+func TestTimeSourcePause(t *testing.T) {
+	ts := NewTimeSource(FPS(fps))
+	p := NewProjectile(FPS(fps), Point{0, 0, 0}, Vector{1, 0, 0}, Vector{})
+
+	ts.Pause()
+	ts.StepFrame()
+
+	ts.Advance()
+	p.UpdateDelta(ts.DeltaTime())
+	stepped := p.Position()
+	if !equal(stepped.X, 1.0/fps) {
+		t.Fatalf("expected a single frame to pass, got %.4f", stepped.X)
+	}
+
+	for i := 0; i < 10; i++ {
+		ts.Advance()
+		p.UpdateDelta(ts.DeltaTime())
+	}
+	if p.Position() != stepped {
+		t.Fatal("expected projectile to stay put while paused")
+	}
+
+	// Paused springs don't move either.
+	s := NewSpring(FPS(fps), 6, 0.3)
+	if pos, vel := ts.Spring(s).Update(0, 2, 1); pos != 0 || vel != 2 {
+		t.Fatalf("expected spring to hold, got %.2f, %.2f", pos, vel)
+	}
+
+	ts.Resume()
+	ts.Advance()
+	if !equal(ts.DeltaTime(), FPS(fps)) {
+		t.Fatalf("expected time to pass after resuming, got %.4f", ts.DeltaTime())
+	}
+}
+
+func TestTimeSourceManySprings(t *testing.T) {
+	ts := NewTimeSource(FPS(fps))
+	ts.SetScale(0.5)
+	ts.Advance()
+
+	// Springs made on the fly, many more than are cached at once, are still
+	// rescaled correctly, as is one in constant use throughout.
+	steady := NewSpring(FPS(fps), 6, 0.3)
+	for i := 0; i < 1000; i++ {
+		s := NewSpring(FPS(fps), 1+float64(i)/100, 0.5)
+		for _, s := range []Spring{s, steady} {
+			want, _ := s.WithDeltaTime(ts.DeltaTime()).Update(0, 0, 1)
+			if got, _ := ts.Spring(s).Update(0, 0, 1); got != want {
+				t.Logf("Want: %.4f", want)
+				t.Logf("Got:  %.4f", got)
+				t.Fatalf("spring %d rescaled incorrectly", i)
+			}
+		}
+	}
+}
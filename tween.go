@@ -0,0 +1,158 @@
+package harmonica
+
+// This file defines a tween, which animates a value from one number to
+// another over a fixed duration, shaped by an easing function. Use it when an
+// animation has to take a set amount of time; use a Spring when it should
+// feel physical.
+//
+// Example usage:
+//
+//    // Run once to initialize a fade that takes half a second.
+//    tween := NewTween(FPS(60), 0, 100, 0.5, EaseOutCubic)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        opacity := tween.Update()
+//        if tween.Done() {
+//            stop()
+//        }
+//    })
+
+import "math"
+
+// RepeatForever makes a tween repeat indefinitely when passed to SetRepeat.
+const RepeatForever = -1
+
+// tweenTolerance is how close, in seconds, a tween has to get to its end to
+// be done.
+const tweenTolerance = 1e-6
+
+// Tween animates a value between two numbers over a fixed duration.
+type Tween struct {
+	from      float64
+	to        float64
+	duration  float64
+	easing    EasingFunc
+	delay     float64
+	repeat    int
+	yoyo      bool
+	elapsed   float64
+	deltaTime float64
+}
+
+// NewTween creates a new tween. It accepts a time delta, the start and end
+// values, the duration in seconds and an easing function. If the easing
+// function is nil the tween is linear.
+func NewTween(deltaTime, from, to, duration float64, easing EasingFunc) *Tween {
+	if easing == nil {
+		easing = Linear
+	}
+	return &Tween{
+		from:      from,
+		to:        to,
+		duration:  math.Max(0, duration),
+		easing:    easing,
+		deltaTime: deltaTime,
+	}
+}
+
+// SetDelay sets how long, in seconds, the tween waits before it starts. The
+// delay only applies once, not to each repeat.
+func (t *Tween) SetDelay(delay float64) {
+	t.delay = math.Max(0, delay)
+}
+
+// SetRepeat sets how many more times the tween plays after the first time.
+// Use RepeatForever to play it indefinitely.
+func (t *Tween) SetRepeat(count int) {
+	if count < 0 {
+		count = RepeatForever
+	}
+	t.repeat = count
+}
+
+// SetYoyo sets whether every other repeat plays backwards, from the end value
+// to the start value, instead of jumping back to the start.
+func (t *Tween) SetYoyo(yoyo bool) {
+	t.yoyo = yoyo
+}
+
+// Update advances the tween by one time step and returns its new value.
+func (t *Tween) Update() float64 {
+	t.elapsed += t.deltaTime
+
+	// Tweens that repeat forever would otherwise let elapsed time grow without
+	// bound, losing precision. Wrapping by two plays keeps yoyos in step.
+	if t.repeat == RepeatForever && t.duration > 0 {
+		if period := 2 * t.duration; t.elapsed-t.delay > period {
+			t.elapsed = t.delay + math.Mod(t.elapsed-t.delay, period)
+		}
+	}
+
+	return t.Value()
+}
+
+// Value returns the current value of the tween.
+func (t *Tween) Value() float64 {
+	return t.ValueAt(t.elapsed)
+}
+
+// ValueAt returns the value of the tween the given number of seconds after it
+// was started, including the delay.
+func (t *Tween) ValueAt(elapsed float64) float64 {
+	p := t.progressAt(elapsed)
+	return t.from + (t.to-t.from)*t.easing(p)
+}
+
+// Progress returns how far through the current play the tween is, from 0 to
+// 1, before easing. When playing backwards in a yoyo it counts down.
+func (t *Tween) Progress() float64 {
+	return t.progressAt(t.elapsed)
+}
+
+// progressAt returns linear progress the given number of seconds after the
+// tween was started.
+func (t *Tween) progressAt(elapsed float64) float64 {
+	elapsed -= t.delay
+	if elapsed <= 0 {
+		return 0
+	}
+
+	var play, p float64
+	switch {
+	case t.finishedAt(elapsed):
+		// Hold at the end of the last play.
+		play, p = float64(t.repeat), 1
+	case t.duration == 0:
+		p = 1
+	default:
+		play = math.Floor(elapsed / t.duration)
+		p = elapsed/t.duration - play
+	}
+
+	if t.yoyo && math.Mod(play, 2) == 1 {
+		p = 1 - p
+	}
+	return p
+}
+
+// Done returns whether the tween has finished playing. Tweens that repeat
+// forever are never done.
+func (t *Tween) Done() bool {
+	return t.finishedAt(t.elapsed - t.delay)
+}
+
+// finishedAt returns whether the tween has finished playing the given number
+// of seconds after its delay. Time deltas from FPS are rounded to whole
+// nanoseconds, so it allows for a little rounding error.
+func (t *Tween) finishedAt(elapsed float64) bool {
+	if t.repeat == RepeatForever {
+		return false
+	}
+	return elapsed >= t.duration*float64(t.repeat+1)-tweenTolerance
+}
+
+// Reset rewinds the tween to the start, including the delay.
+func (t *Tween) Reset() {
+	t.elapsed = 0
+}
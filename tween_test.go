@@ -0,0 +1,87 @@
+package harmonica_test
+
+import (
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestTween(t *testing.T) {
+	tw := NewTween(FPS(fps), 10, 20, 0.5, EaseInQuad)
+
+	for i := 0; i < fps/4; i++ {
+		tw.Update()
+	}
+	if !equal(tw.Value(), 12.5) {
+		t.Logf("Want: %.2f", 12.5)
+		t.Logf("Got:  %.2f", tw.Value())
+		t.Fatal("value half way through unexpected")
+	}
+	if tw.Done() {
+		t.Fatal("tween finished early")
+	}
+
+	for i := 0; i < fps/4; i++ {
+		tw.Update()
+	}
+	if !tw.Done() || tw.Value() != 20 {
+		t.Fatalf("expected tween to finish at 20, got %.2f", tw.Value())
+	}
+
+	// It holds its end value.
+	tw.Update()
+	if tw.Value() != 20 {
+		t.Fatalf("expected tween to hold at 20, got %.2f", tw.Value())
+	}
+
+	tw.Reset()
+	if tw.Done() || tw.Value() != 10 {
+		t.Fatalf("expected tween to rewind to 10, got %.2f", tw.Value())
+	}
+}
+
+func TestTweenDelay(t *testing.T) {
+	tw := NewTween(FPS(fps), 0, 1, 1, nil)
+	tw.SetDelay(0.5)
+
+	for i := 0; i < fps/2; i++ {
+		if v := tw.Update(); v > 0.01 {
+			t.Fatalf("tween started during delay on frame %d: %.2f", i, v)
+		}
+	}
+	for i := 0; i < fps/2; i++ {
+		tw.Update()
+	}
+	if !equal(tw.Value(), 0.5) {
+		t.Fatalf("expected tween half way after its delay, got %.2f", tw.Value())
+	}
+}
+
+func TestTweenRepeatYoyo(t *testing.T) {
+	tw := NewTween(FPS(fps), 0, 1, 1, nil)
+	tw.SetRepeat(2)
+	tw.SetYoyo(true)
+
+	want := []float64{1, 0, 1}
+	for play, w := range want {
+		for i := 0; i < fps; i++ {
+			tw.Update()
+		}
+		if !equal(tw.Value(), w) {
+			t.Fatalf("after play %d: got %.2f, want %.2f", play+1, tw.Value(), w)
+		}
+	}
+	if !tw.Done() {
+		t.Fatal("expected tween to be done after three plays")
+	}
+
+	forever := NewTween(FPS(fps), 0, 1, 1, nil)
+	forever.SetRepeat(RepeatForever)
+	forever.SetYoyo(true)
+	for i := 0; i < fps*60*5+fps/2; i++ {
+		forever.Update()
+	}
+	if forever.Done() || !equal(forever.Value(), 0.5) {
+		t.Fatalf("expected endless tween half way, got %.2f", forever.Value())
+	}
+}
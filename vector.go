@@ -0,0 +1,223 @@
+package harmonica
+
+// This file defines the math on points and vectors.
+//
+// Points and vectors convert between each other in the usual ways: the
+// difference between two points is a vector, and a point offset by a vector
+// is another point.
+//
+// Example usage:
+//
+//    a := Point{1, 2, 0}
+//    b := Point{4, 6, 0}
+//
+//    dir := b.Sub(a)          // Vector{3, 4, 0}
+//    dist := dir.Length()     // 5
+//    mid := a.Lerp(b, 0.5)    // Point{2.5, 4, 0}
+//    c := a.Add(dir.Scale(2)) // Point{7, 10, 0}
+//
+// Angles are in radians. 2D helpers operate in the XY plane and leave Z
+// untouched.
+
+import "math"
+
+// Add returns the point offset by the vector.
+func (p Point) Add(v Vector) Point {
+	return Point{p.X + v.X, p.Y + v.Y, p.Z + v.Z}
+}
+
+// Sub returns the vector from q to p.
+func (p Point) Sub(q Point) Vector {
+	return Vector{p.X - q.X, p.Y - q.Y, p.Z - q.Z}
+}
+
+// Distance returns the euclidean distance between two points.
+func (p Point) Distance(q Point) float64 {
+	return p.Sub(q).Length()
+}
+
+// DistanceSquared returns the squared euclidean distance between two points.
+// It's cheaper than Distance and useful for comparing distances.
+func (p Point) DistanceSquared(q Point) float64 {
+	return p.Sub(q).LengthSquared()
+}
+
+// Lerp linearly interpolates between two points. A t of 0 returns p and
+// a t of 1 returns q.
+func (p Point) Lerp(q Point, t float64) Point {
+	return Point{
+		X: p.X + (q.X-p.X)*t,
+		Y: p.Y + (q.Y-p.Y)*t,
+		Z: p.Z + (q.Z-p.Z)*t,
+	}
+}
+
+// Vector returns the vector from the origin to the point.
+func (p Point) Vector() Vector {
+	return Vector(p)
+}
+
+// Point returns the point the vector points to from the origin.
+func (v Vector) Point() Point {
+	return Point(v)
+}
+
+// Add returns the sum of two vectors.
+func (v Vector) Add(w Vector) Vector {
+	return Vector{v.X + w.X, v.Y + w.Y, v.Z + w.Z}
+}
+
+// Sub returns the difference of two vectors.
+func (v Vector) Sub(w Vector) Vector {
+	return Vector{v.X - w.X, v.Y - w.Y, v.Z - w.Z}
+}
+
+// Scale returns the vector multiplied by a scalar.
+func (v Vector) Scale(s float64) Vector {
+	return Vector{v.X * s, v.Y * s, v.Z * s}
+}
+
+// Neg returns the vector pointing in the opposite direction.
+func (v Vector) Neg() Vector {
+	return Vector{-v.X, -v.Y, -v.Z}
+}
+
+// Dot returns the dot product of two vectors.
+func (v Vector) Dot(w Vector) float64 {
+	return v.X*w.X + v.Y*w.Y + v.Z*w.Z
+}
+
+// Cross returns the cross product of two vectors, which is perpendicular to
+// both. For vectors in the XY plane only the Z component is non-zero, and it
+// equals the 2D cross product.
+func (v Vector) Cross(w Vector) Vector {
+	return Vector{
+		X: v.Y*w.Z - v.Z*w.Y,
+		Y: v.Z*w.X - v.X*w.Z,
+		Z: v.X*w.Y - v.Y*w.X,
+	}
+}
+
+// Length returns the magnitude of the vector.
+func (v Vector) Length() float64 {
+	return math.Sqrt(v.LengthSquared())
+}
+
+// LengthSquared returns the squared magnitude of the vector. It's cheaper than
+// Length and useful for comparing magnitudes.
+func (v Vector) LengthSquared() float64 {
+	return v.Dot(v)
+}
+
+// Normalize returns a unit vector pointing in the same direction. The zero
+// vector has no direction and is returned as-is.
+func (v Vector) Normalize() Vector {
+	l := v.Length()
+	if l < epsilon {
+		return Vector{}
+	}
+	return v.Scale(1 / l)
+}
+
+// Lerp linearly interpolates between two vectors. A t of 0 returns v and
+// a t of 1 returns w.
+func (v Vector) Lerp(w Vector, t float64) Vector {
+	return v.Add(w.Sub(v).Scale(t))
+}
+
+// Reflect returns the vector reflected off a surface with the given normal,
+// like a ball bouncing off a wall. The normal needn't be normalized.
+func (v Vector) Reflect(normal Vector) Vector {
+	n := normal.Normalize()
+	return v.Sub(n.Scale(2 * v.Dot(n)))
+}
+
+// Project returns the projection of the vector onto another.
+func (v Vector) Project(onto Vector) Vector {
+	l := onto.LengthSquared()
+	if l < epsilon {
+		return Vector{}
+	}
+	return onto.Scale(v.Dot(onto) / l)
+}
+
+// AngleTo returns the unsigned angle between two vectors, from 0 to π.
+func (v Vector) AngleTo(w Vector) float64 {
+	// atan2 of the cross and dot products is better conditioned than acos of
+	// the normalized dot product for nearly parallel vectors.
+	return math.Atan2(v.Cross(w).Length(), v.Dot(w))
+}
+
+// Rotate returns the vector rotated counter-clockwise around the Z axis, which
+// is rotation in the XY plane.
+func (v Vector) Rotate(angle float64) Vector {
+	sin, cos := math.Sincos(angle)
+	return Vector{
+		X: v.X*cos - v.Y*sin,
+		Y: v.X*sin + v.Y*cos,
+		Z: v.Z,
+	}
+}
+
+// RotateAround returns the vector rotated counter-clockwise around an
+// arbitrary axis when looking down the axis. The axis needn't be normalized.
+func (v Vector) RotateAround(axis Vector, angle float64) Vector {
+	k := axis.Normalize()
+	if k == (Vector{}) {
+		return v
+	}
+
+	// Rodrigues' rotation formula.
+	sin, cos := math.Sincos(angle)
+	return v.Scale(cos).
+		Add(k.Cross(v).Scale(sin)).
+		Add(k.Scale(k.Dot(v) * (1 - cos)))
+}
+
+// Perp returns the vector rotated a quarter turn counter-clockwise in the XY
+// plane.
+func (v Vector) Perp() Vector {
+	return Vector{-v.Y, v.X, v.Z}
+}
+
+// Angle returns the direction of the vector in the XY plane, measured
+// counter-clockwise from the X axis, from -π to π.
+func (v Vector) Angle() float64 {
+	return math.Atan2(v.Y, v.X)
+}
+
+// Polar returns a vector in the XY plane from polar coordinates, with the
+// angle measured counter-clockwise from the X axis.
+func Polar(radius, angle float64) Vector {
+	sin, cos := math.Sincos(angle)
+	return Vector{radius * cos, radius * sin, 0}
+}
+
+// Polar returns the polar coordinates of the vector in the XY plane. It's the
+// inverse of the Polar function.
+func (v Vector) Polar() (radius, angle float64) {
+	return math.Hypot(v.X, v.Y), v.Angle()
+}
+
+// Spherical returns a vector from spherical coordinates. The inclination is
+// the angle from the Z axis and the azimuth is the angle in the XY plane,
+// measured counter-clockwise from the X axis.
+func Spherical(radius, inclination, azimuth float64) Vector {
+	sinInc, cosInc := math.Sincos(inclination)
+	sinAz, cosAz := math.Sincos(azimuth)
+	return Vector{
+		X: radius * sinInc * cosAz,
+		Y: radius * sinInc * sinAz,
+		Z: radius * cosInc,
+	}
+}
+
+// Spherical returns the spherical coordinates of the vector. It's the inverse
+// of the Spherical function.
+func (v Vector) Spherical() (radius, inclination, azimuth float64) {
+	radius = v.Length()
+	if radius < epsilon {
+		return 0, 0, 0
+	}
+	return radius, math.Acos(math.Max(-1, math.Min(1, v.Z/radius))), v.Angle()
+}
@@ -0,0 +1,110 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func equalVector(a, b Vector) bool {
+	return equal(a.X, b.X) && equal(a.Y, b.Y) && equal(a.Z, b.Z)
+}
+
+func TestPointMath(t *testing.T) {
+	a := Point{1, 2, 3}
+	b := Point{4, 6, 3}
+
+	if got := b.Sub(a); got != (Vector{3, 4, 0}) {
+		t.Fatalf("Sub: got %+v", got)
+	}
+	if got := a.Add(Vector{3, 4, 0}); got != b {
+		t.Fatalf("Add: got %+v", got)
+	}
+	if got := a.Distance(b); !equal(got, 5) {
+		t.Fatalf("Distance: got %.2f", got)
+	}
+	if got := a.DistanceSquared(b); !equal(got, 25) {
+		t.Fatalf("DistanceSquared: got %.2f", got)
+	}
+	if got := a.Lerp(b, 0.5); got != (Point{2.5, 4, 3}) {
+		t.Fatalf("Lerp: got %+v", got)
+	}
+	if got := a.Vector().Point(); got != a {
+		t.Fatalf("Vector/Point round trip: got %+v", got)
+	}
+}
+
+func TestVectorMath(t *testing.T) {
+	v := Vector{1, 2, 3}
+	w := Vector{4, 5, 6}
+
+	for _, tc := range []struct {
+		name      string
+		got, want Vector
+	}{
+		{"Add", v.Add(w), Vector{5, 7, 9}},
+		{"Sub", v.Sub(w), Vector{-3, -3, -3}},
+		{"Scale", v.Scale(2), Vector{2, 4, 6}},
+		{"Neg", v.Neg(), Vector{-1, -2, -3}},
+		{"Cross", v.Cross(w), Vector{-3, 6, -3}},
+		{"Normalize", Vector{3, 0, 4}.Normalize(), Vector{0.6, 0, 0.8}},
+		{"Normalize zero", Vector{}.Normalize(), Vector{}},
+		{"Lerp", v.Lerp(w, 0.25), Vector{1.75, 2.75, 3.75}},
+		{"Reflect", Vector{1, -1, 0}.Reflect(Vector{0, 2, 0}), Vector{1, 1, 0}},
+		{"Project", v.Project(Vector{0, 3, 0}), Vector{0, 2, 0}},
+		{"Rotate", Vector{1, 0, 7}.Rotate(math.Pi / 2), Vector{0, 1, 7}},
+		{"RotateAround", Vector{0, 1, 0}.RotateAround(Vector{1, 0, 0}, math.Pi/2), Vector{0, 0, 1}},
+		{"Perp", Vector{1, 2, 0}.Perp(), Vector{-2, 1, 0}},
+	} {
+		if !equalVector(tc.got, tc.want) {
+			t.Logf("Want: %+v", tc.want)
+			t.Logf("Got:  %+v", tc.got)
+			t.Fatalf("%s unexpected", tc.name)
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"Dot", v.Dot(w), 32},
+		{"Length", Vector{2, 3, 6}.Length(), 7},
+		{"LengthSquared", Vector{2, 3, 6}.LengthSquared(), 49},
+		{"Angle", Vector{0, -1, 0}.Angle(), -math.Pi / 2},
+		{"AngleTo", Vector{1, 0, 0}.AngleTo(Vector{-1, 1, 0}), 3 * math.Pi / 4},
+	} {
+		if !equal(tc.got, tc.want) {
+			t.Logf("Want: %.4f", tc.want)
+			t.Logf("Got:  %.4f", tc.got)
+			t.Fatalf("%s unexpected", tc.name)
+		}
+	}
+}
+
+func TestPolar(t *testing.T) {
+	v := Polar(2, math.Pi/3)
+	if !equalVector(v, Vector{1, math.Sqrt(3), 0}) {
+		t.Fatalf("Polar: got %+v", v)
+	}
+
+	r, a := v.Polar()
+	if !equal(r, 2) || !equal(a, math.Pi/3) {
+		t.Logf("Want: (%.2f, %.2f)", 2.0, math.Pi/3)
+		t.Logf("Got:  (%.2f, %.2f)", r, a)
+		t.Fatal("polar round trip unexpected")
+	}
+}
+
+func TestSpherical(t *testing.T) {
+	v := Spherical(3, math.Pi/2, math.Pi/2)
+	if !equalVector(v, Vector{0, 3, 0}) {
+		t.Fatalf("Spherical: got %+v", v)
+	}
+
+	w := Vector{1, -2, 2}
+	r, inc, az := w.Spherical()
+	if !equalVector(Spherical(r, inc, az), w) {
+		t.Fatal("spherical round trip unexpected")
+	}
+}
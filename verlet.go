@@ -0,0 +1,196 @@
+package harmonica
+
+// This file defines a Verlet integrator with distance constraints, which is
+// well suited for ropes, chains and hanging signs. Points move freely under
+// gravity while constraints keep connected points at a fixed distance from
+// one another. Pinned points stay put, which is how ropes are hung.
+//
+// Example usage:
+//
+//    // Run once to initialize a rope hanging from (10, 0) with 12 segments.
+//    rope := NewRope(FPS(60), TerminalGravity, Point{10, 0, 0}, Point{30, 0, 0}, 12)
+//
+//    // Update on every frame.
+//    someUpdateLoop(func() {
+//        rope.Update()
+//        for i := 0; i < rope.Len(); i++ {
+//            draw(rope.Position(i))
+//        }
+//    })
+//
+// For background on the technique see:
+// https://en.wikipedia.org/wiki/Verlet_integration
+
+// DefaultVerletIterations is the number of times constraints are solved per
+// update when using NewRope. More iterations make constraints stiffer.
+const DefaultVerletIterations = 10
+
+// Verlet simulates a set of points connected by distance constraints.
+type Verlet struct {
+	points      []verletPoint
+	constraints []distanceConstraint
+	gravity     Vector
+	damping     float64
+	deltaTime   float64
+	iterations  int
+}
+
+type verletPoint struct {
+	pos, prev Point
+	pinned    bool
+}
+
+type distanceConstraint struct {
+	a, b   int
+	length float64
+}
+
+// NewVerlet creates an empty Verlet simulation. It accepts a time delta, the
+// gravity acting on all points, such as Gravity or TerminalGravity, and the
+// number of times constraints are solved per update.
+func NewVerlet(deltaTime float64, gravity Vector, iterations int) *Verlet {
+	if iterations < 1 {
+		iterations = 1
+	}
+	return &Verlet{
+		gravity:    gravity,
+		deltaTime:  deltaTime,
+		iterations: iterations,
+	}
+}
+
+// NewRope creates a rope of the given number of segments running from start
+// to end, with the start pinned in place. The rope's length is the distance
+// between the two points.
+func NewRope(deltaTime float64, gravity Vector, start, end Point, segments int) *Verlet {
+	if segments < 1 {
+		segments = 1
+	}
+
+	v := NewVerlet(deltaTime, gravity, DefaultVerletIterations)
+	v.AddPoint(start, true)
+	for i := 1; i <= segments; i++ {
+		v.AddPoint(start.Lerp(end, float64(i)/float64(segments)), false)
+		v.Connect(i-1, i)
+	}
+	return v
+}
+
+// AddPoint adds a point to the simulation at rest and returns its index.
+func (v *Verlet) AddPoint(pos Point, pinned bool) int {
+	v.points = append(v.points, verletPoint{pos: pos, prev: pos, pinned: pinned})
+	return len(v.points) - 1
+}
+
+// Connect constrains two points to stay at their current distance from one
+// another.
+func (v *Verlet) Connect(a, b int) {
+	v.ConnectLength(a, b, v.points[a].pos.Distance(v.points[b].pos))
+}
+
+// ConnectLength constrains two points to stay at the given distance from one
+// another.
+func (v *Verlet) ConnectLength(a, b int, length float64) {
+	v.constraints = append(v.constraints, distanceConstraint{a, b, length})
+}
+
+// Len returns the number of points.
+func (v *Verlet) Len() int {
+	return len(v.points)
+}
+
+// Position returns the position of a point.
+func (v *Verlet) Position(i int) Point {
+	return v.points[i].pos
+}
+
+// Velocity returns the velocity of a point.
+func (v *Verlet) Velocity(i int) Vector {
+	p := v.points[i]
+	return p.pos.Sub(p.prev).Scale(1 / v.deltaTime)
+}
+
+// Pinned reports whether a point is pinned in place.
+func (v *Verlet) Pinned(i int) bool {
+	return v.points[i].pinned
+}
+
+// SetPinned pins a point in place or frees it.
+func (v *Verlet) SetPinned(i int, pinned bool) {
+	v.points[i].pinned = pinned
+}
+
+// SetPosition moves a point without giving it any velocity. Use it to drag
+// pinned points around.
+func (v *Verlet) SetPosition(i int, pos Point) {
+	v.points[i].pos = pos
+	v.points[i].prev = pos
+}
+
+// SetGravity sets the gravity acting on all points.
+func (v *Verlet) SetGravity(gravity Vector) {
+	v.gravity = gravity
+}
+
+// SetDamping sets how much velocity points lose per second, from 0 for none
+// to 1 for all of it. A little damping helps ropes settle.
+func (v *Verlet) SetDamping(damping float64) {
+	v.damping = damping
+}
+
+// Update advances the simulation by one time step.
+func (v *Verlet) Update() {
+	var (
+		dt2  = v.deltaTime * v.deltaTime
+		keep = 1 - v.damping*v.deltaTime
+		acc  = v.gravity.Scale(dt2)
+	)
+	if keep < 0 {
+		keep = 0
+	}
+
+	for i := range v.points {
+		p := &v.points[i]
+		if p.pinned {
+			p.prev = p.pos
+			continue
+		}
+		vel := p.pos.Sub(p.prev).Scale(keep)
+		p.prev = p.pos
+		p.pos = p.pos.Add(vel).Add(acc)
+	}
+
+	for n := 0; n < v.iterations; n++ {
+		for _, c := range v.constraints {
+			v.solve(c)
+		}
+	}
+}
+
+// solve moves two constrained points towards, or away from, each other until
+// they're at the constraint's length. Pinned points don't move, so their
+// partners move the whole way.
+func (v *Verlet) solve(c distanceConstraint) {
+	a, b := &v.points[c.a], &v.points[c.b]
+	if a.pinned && b.pinned {
+		return
+	}
+
+	d := b.pos.Sub(a.pos)
+	dist := d.Length()
+	if dist < epsilon {
+		return
+	}
+	correction := d.Scale((dist - c.length) / dist)
+
+	switch {
+	case a.pinned:
+		b.pos = b.pos.Add(correction.Neg())
+	case b.pinned:
+		a.pos = a.pos.Add(correction)
+	default:
+		half := correction.Scale(0.5)
+		a.pos = a.pos.Add(half)
+		b.pos = b.pos.Add(half.Neg())
+	}
+}
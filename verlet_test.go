@@ -0,0 +1,67 @@
+package harmonica_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/charmbracelet/harmonica"
+)
+
+func TestRope(t *testing.T) {
+	const segments = 10
+	start := Point{0, 0, 0}
+	rope := NewRope(FPS(fps), Gravity, start, Point{20, 0, 0}, segments)
+	rope.SetDamping(1)
+
+	if rope.Len() != segments+1 {
+		t.Fatalf("expected %d points, got %d", segments+1, rope.Len())
+	}
+
+	for i := 0; i < fps*20; i++ {
+		rope.Update()
+	}
+
+	if rope.Position(0) != start {
+		t.Fatal("pinned point moved")
+	}
+
+	// The rope should hang straight down and keep its segment lengths.
+	end := rope.Position(segments)
+	if math.Abs(end.X) > 0.5 || math.Abs(end.Y+20) > 0.5 {
+		t.Logf("Want: (%.2f, %.2f)", 0.0, -20.0)
+		t.Logf("Got:  (%.2f, %.2f)", end.X, end.Y)
+		t.Fatal("rope end unexpected")
+	}
+	for i := 1; i < rope.Len(); i++ {
+		if d := rope.Position(i - 1).Distance(rope.Position(i)); math.Abs(d-2) > 0.05 {
+			t.Fatalf("segment %d has length %.2f, want %.2f", i, d, 2.0)
+		}
+	}
+
+	// And it should come to rest.
+	if v := rope.Velocity(segments).Length(); v > 0.1 {
+		t.Fatalf("rope still moving at %.2f", v)
+	}
+}
+
+func TestVerletPinnedPair(t *testing.T) {
+	v := NewVerlet(FPS(fps), TerminalGravity, 5)
+	a := v.AddPoint(Point{0, 0, 0}, true)
+	b := v.AddPoint(Point{10, 0, 0}, true)
+	c := v.AddPoint(Point{5, 0, 0}, false)
+	v.ConnectLength(a, c, 6)
+	v.ConnectLength(b, c, 6)
+	v.SetDamping(2)
+
+	for i := 0; i < fps*10; i++ {
+		v.Update()
+	}
+
+	// Hanging from two pins, the middle point sags below them.
+	p := v.Position(c)
+	if !equal(p.X, 5) || math.Abs(p.Y-math.Sqrt(11)) > 0.1 {
+		t.Logf("Want: (%.2f, %.2f)", 5.0, math.Sqrt(11))
+		t.Logf("Got:  (%.2f, %.2f)", p.X, p.Y)
+		t.Fatal("sagging point unexpected")
+	}
+}